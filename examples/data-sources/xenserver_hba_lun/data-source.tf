@@ -0,0 +1,9 @@
+data "xenserver_host" "host" {}
+
+data "xenserver_hba_lun" "lun" {
+  host = data.xenserver_host.host.data_items[0].uuid
+}
+
+output "hba_lun_output" {
+  value = data.xenserver_hba_lun.lun.data_items
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+# Probe the IQNs of the iSCSI target
+data "xenserver_iscsi_probe" "target" {
+  target = "192.0.2.10"
+}
+
+# Probe the LUNs of the IQN
+data "xenserver_iscsi_probe" "lun" {
+  target     = "192.0.2.10"
+  target_iqn = data.xenserver_iscsi_probe.target.iqns[0]
+}
+
+output "iscsi_probe_output" {
+  value = data.xenserver_iscsi_probe.lun.data_items
+}
\ No newline at end of file
@@ -4,4 +4,15 @@ data "xenserver_network" "network" {
 
 output "network_output" {
   value = data.xenserver_network.network.data_items
+}
+
+# Get the VLAN networks with tag 100
+data "xenserver_network" "vlan_network" {
+  filter = [
+    {
+      name  = "vlan"
+      value = "100"
+    }
+  ]
+  expect_count = 1
 }
\ No newline at end of file
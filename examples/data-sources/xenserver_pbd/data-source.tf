@@ -0,0 +1,17 @@
+data "xenserver_sr" "sr" {
+  name_label = "Local storage"
+}
+
+data "xenserver_pbd" "pbd" {
+  sr_uuid = data.xenserver_sr.sr.data_items[0].uuid
+  filter = [
+    {
+      name  = "currently_attached"
+      value = "false"
+    }
+  ]
+}
+
+output "pbd_output" {
+  value = data.xenserver_pbd.pbd.data_items
+}
\ No newline at end of file
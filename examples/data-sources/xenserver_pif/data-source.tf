@@ -6,3 +6,29 @@ data "xenserver_pif" "pif" {
 output "pif_data_out" {
   value = data.xenserver_pif.pif.data_items
 }
+
+
+# Get the physical PIFs which are attached on the host
+data "xenserver_host" "host" {}
+
+data "xenserver_pif" "attached_pif" {
+  filter = [
+    {
+      name  = "host"
+      value = data.xenserver_host.host.data_items[0].uuid
+    },
+    {
+      name  = "physical"
+      value = "true"
+    },
+    {
+      name  = "currently_attached"
+      value = "true"
+    },
+    {
+      name     = "device"
+      operator = "regex"
+      value    = "^eth[0-9]+$"
+    }
+  ]
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+data "xenserver_sr" "iso" {
+  name_label = "ISO library"
+}
+
+# Find the ISO in the ISO library
+data "xenserver_vdi" "iso" {
+  sr_uuid      = data.xenserver_sr.iso.data_items[0].uuid
+  name_label   = "ubuntu-24.04-live-server-amd64.iso"
+  expect_count = 1
+}
+
+output "vdi_output" {
+  value = data.xenserver_vdi.iso.data_items
+}
+
+# Find the disks which are not attached to any VM
+data "xenserver_vdi" "disks" {
+  type          = "user"
+  is_a_snapshot = false
+}
+
+output "orphaned_vdi_output" {
+  value = [for vdi in data.xenserver_vdi.disks.data_items : vdi.uuid if length(vdi.vbds) == 0]
+}
\ No newline at end of file
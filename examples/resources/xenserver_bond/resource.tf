@@ -0,0 +1,20 @@
+data "xenserver_nic" "nic" {
+  network_type = "bond"
+}
+
+resource "xenserver_bond" "bond" {
+  name_label        = "Bond network"
+  name_description  = "LACP bond of NIC 0 and NIC 1"
+  nics              = [data.xenserver_nic.nic.data_items[0], data.xenserver_nic.nic.data_items[1]]
+  mode              = "lacp"
+  hashing_algorithm = "tcpudp_ports"
+  lacp_timeout      = "fast"
+  mtu               = 9000
+}
+
+# Create a VLAN network over the bond
+resource "xenserver_network_vlan" "vlan" {
+  name_label = "VLAN over bond"
+  vlan_tag   = 10
+  nic        = xenserver_bond.bond.nic
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+data "xenserver_pif" "management_pif" {
+  management = true
+}
+
+resource "xenserver_cluster" "cluster" {
+  network_uuid = data.xenserver_pif.management_pif.data_items[0].network
+}
+
+data "xenserver_iscsi_probe" "lun" {
+  target     = "192.0.2.10"
+  target_iqn = "iqn.2010-01.com.example:storage"
+}
+
+resource "xenserver_sr_iscsi" "gfs2" {
+  name_label = "Test GFS2 storage repository"
+  type       = "gfs2"
+  target     = "192.0.2.10"
+  target_iqn = "iqn.2010-01.com.example:storage"
+  scsi_id    = data.xenserver_iscsi_probe.lun.scsi_ids[0]
+
+  # The clustered SR must be destroyed before the cluster
+  depends_on = [xenserver_cluster.cluster]
+}
\ No newline at end of file
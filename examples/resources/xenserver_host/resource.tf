@@ -0,0 +1,17 @@
+data "xenserver_host" "host" {
+  is_coordinator = true
+}
+
+resource "xenserver_host" "host" {
+  uuid               = data.xenserver_host.host.data_items[0].uuid
+  name_label         = "Test host"
+  hostname           = "test-host"
+  ntp_mode           = "custom"
+  ntp_servers        = ["0.pool.ntp.org", "1.pool.ntp.org"]
+  timezone           = "UTC"
+  syslog_destination = "syslog.example.com"
+  ssh_enabled        = true
+  other_config = {
+    "flag" = "1"
+  }
+}
\ No newline at end of file
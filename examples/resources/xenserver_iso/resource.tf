@@ -0,0 +1,18 @@
+resource "xenserver_sr_nfs" "iso_library" {
+  name_label       = "NFS ISO library"
+  type             = "iso"
+  version          = "3"
+  storage_location = "server:/path"
+}
+
+resource "xenserver_iso" "ubuntu" {
+  sr_uuid         = xenserver_sr_nfs.iso_library.uuid
+  source_file     = "/path/to/ubuntu-24.04-live-server-amd64.iso"
+  source_checksum = filesha256("/path/to/ubuntu-24.04-live-server-amd64.iso")
+}
+
+# Use the uploaded ISO as the CD-ROM of a VM
+# resource "xenserver_vm" "vm" {
+#   ...
+#   cdrom = xenserver_iso.ubuntu.name_label
+# }
\ No newline at end of file
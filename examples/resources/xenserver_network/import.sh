@@ -0,0 +1 @@
+terraform import xenserver_network.internal 00000000-0000-0000-0000-000000000000
\ No newline at end of file
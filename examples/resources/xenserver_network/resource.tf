@@ -0,0 +1,16 @@
+resource "xenserver_network" "internal" {
+  name_label       = "Test internal network"
+  name_description = "test description"
+  mtu              = 1500
+  tags             = ["internal"]
+  other_config = {
+    "flag" = "1"
+  }
+}
+
+resource "xenserver_network" "nbd" {
+  name_label           = "Test NBD network"
+  nic                  = "NIC 1"
+  purpose              = ["nbd"]
+  default_locking_mode = "unlocked"
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+data "xenserver_nic" "sriov_nic" {
+  network_type = "sriov"
+}
+
+resource "xenserver_network_sriov" "sriov" {
+  name_label       = "Test SR-IOV network"
+  name_description = "test description"
+  nic              = data.xenserver_nic.sriov_nic.data_items[0]
+}
+
+output "sriov_requires_reboot" {
+  value = xenserver_network_sriov.sriov.requires_reboot
+}
+
+output "sriov_remaining_capacity" {
+  value = xenserver_network_sriov.sriov.remaining_capacity
+}
+
+# The SR-IOV network can be attached to the VM network interface
+# network_interface = [
+#   {
+#     network_uuid = xenserver_network_sriov.sriov.uuid
+#   },
+# ]
\ No newline at end of file
@@ -0,0 +1,13 @@
+resource "xenserver_network_tunnel" "vxlan" {
+  name_label       = "Test cross-server private network"
+  name_description = "test description"
+  mtu              = 1450
+  protocol         = "vxlan"
+  other_config = {
+    "flag" = "1"
+  }
+}
+
+output "tunnel_status" {
+  value = xenserver_network_tunnel.vxlan.status
+}
\ No newline at end of file
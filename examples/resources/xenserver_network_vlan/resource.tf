@@ -9,3 +9,14 @@ resource "xenserver_network_vlan" "vlan" {
     "flag" = "1"
   }
 }
+
+
+# Create the VLAN only on the hosts which have the NIC
+data "xenserver_host" "host" {}
+
+resource "xenserver_network_vlan" "vlan_on_host" {
+  name_label = "Test external network on host"
+  vlan_tag   = 2
+  nic        = "NIC 2"
+  hosts      = [data.xenserver_host.host.data_items[0].uuid]
+}
\ No newline at end of file
@@ -13,6 +13,19 @@ resource "xenserver_pif_configure" "pif_update" {
   }
 }
 
+# Configure IPv6 and move the host management interface to the PIF
+resource "xenserver_pif_configure" "pif_management" {
+  uuid = data.xenserver_pif.pif_eth1.data_items[0].uuid
+  ipv6 = {
+    mode    = "Static"
+    ipv6    = "2001:db8::1/64"
+    gateway = "2001:db8::ffff"
+  }
+  primary_address_type = "IPv6"
+  management           = true
+  check_ip_timeout     = 120
+}
+
 # Update multiple PIFs configuration
 locals {
   pif_data = tomap({for element in data.xenserver_pif.pif_eth1.data_items: element.uuid => element})
@@ -0,0 +1,22 @@
+# Allocate the storage IP addresses of all the hosts from a range
+resource "xenserver_pif_configure_pool" "storage" {
+  nic        = "NIC 1"
+  name_label = "Storage"
+  mode       = "Static"
+  netmask    = "255.255.255.0"
+  ip_range = {
+    start = "192.0.2.10"
+    end   = "192.0.2.50"
+  }
+  # Pin the IP address of a specific host
+  hosts = {
+    "00000000-0000-0000-0000-000000000000" = {
+      ip = "192.0.2.100"
+    }
+  }
+  disallow_unplug = true
+}
+
+output "storage_ip_addresses" {
+  value = xenserver_pif_configure_pool.storage.ip_addresses
+}
\ No newline at end of file
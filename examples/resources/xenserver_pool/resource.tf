@@ -30,6 +30,7 @@ resource "xenserver_pool" "pool" {
   name_label   = "pool"
   default_sr = xenserver_sr_nfs.nfs.uuid
   management_network = data.xenserver_pif.pif.data_items[0].network
+  igmp_snooping_enabled = true
 }
 
 # Join supporter into the pool
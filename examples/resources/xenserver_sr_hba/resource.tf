@@ -0,0 +1,7 @@
+data "xenserver_hba_lun" "lun" {}
+
+resource "xenserver_sr_hba" "hba" {
+  name_label       = "Test HBA storage repository"
+  name_description = "A test HBA storage repository"
+  scsi_id          = data.xenserver_hba_lun.lun.data_items[0].scsi_id
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+data "xenserver_iscsi_probe" "lun" {
+  target     = "192.0.2.10"
+  target_iqn = "iqn.2010-01.com.example:storage"
+}
+
+resource "xenserver_sr_iscsi" "iscsi" {
+  name_label       = "Test iSCSI storage repository"
+  name_description = "A test iSCSI storage repository"
+  target           = "192.0.2.10"
+  target_iqn       = "iqn.2010-01.com.example:storage"
+  scsi_id          = data.xenserver_iscsi_probe.lun.scsi_ids[0]
+  chap_username    = "chap-user"
+  chap_password    = "chap-password"
+}
\ No newline at end of file
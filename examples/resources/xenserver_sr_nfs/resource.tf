@@ -12,3 +12,12 @@ resource "xenserver_sr_nfs" "nfs_iso_test" {
   version          = "4"
   storage_location = "server:/path"
 }
+
+# Reattach the existing SR on the NFS server without formatting it, and only detach it when destroyed
+resource "xenserver_sr_nfs" "nfs_attach_test" {
+  name_label       = "NFS virtual disk storage"
+  version          = "3"
+  storage_location = "server:/path"
+  mode             = "attach"
+  destroy_behavior = "detach"
+}
@@ -30,3 +30,11 @@ resource "xenserver_vdi" "vdi" {
   read_only        = true
   type             = "system"
 }
+
+# Copy a VDI to another SR
+resource "xenserver_vdi" "copy" {
+  name_label      = "Test copy VDI"
+  sr_uuid         = xenserver_sr_nfs.nfs.uuid
+  source_vdi_uuid = xenserver_vdi.vdi.uuid
+  source_mode     = "copy"
+}
\ No newline at end of file
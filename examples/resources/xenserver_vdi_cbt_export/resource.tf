@@ -0,0 +1,36 @@
+resource "xenserver_vdi" "vdi" {
+  name_label   = "Test VDI"
+  sr_uuid      = data.xenserver_sr.sr.data_items[0].uuid
+  virtual_size = 100 * 1024 * 1024 * 1024
+  cbt_enabled  = true
+}
+
+# The changed blocks are read from the NBD server of the host
+resource "xenserver_network" "nbd" {
+  name_label = "NBD network"
+  nic        = "NIC 1"
+  purpose    = ["nbd"]
+}
+
+resource "xenserver_snapshot" "monday" {
+  name_label = "Monday backup"
+  vm_uuid    = xenserver_vm.vm.uuid
+}
+
+resource "xenserver_snapshot" "tuesday" {
+  name_label = "Tuesday backup"
+  vm_uuid    = xenserver_vm.vm.uuid
+  depends_on = [xenserver_snapshot.monday]
+}
+
+# Export the blocks changed from Monday to Tuesday
+resource "xenserver_vdi_cbt_export" "tuesday" {
+  vdi_from_uuid = xenserver_snapshot.monday.vdis[xenserver_vdi.vdi.uuid]
+  vdi_to_uuid   = xenserver_snapshot.tuesday.vdis[xenserver_vdi.vdi.uuid]
+  output_file   = "/backup/vdi-tuesday.raw"
+  depends_on    = [xenserver_network.nbd]
+}
+
+output "changed_block_count" {
+  value = xenserver_vdi_cbt_export.tuesday.changed_block_count
+}
\ No newline at end of file
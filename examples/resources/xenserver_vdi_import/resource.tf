@@ -0,0 +1,21 @@
+data "xenserver_sr" "sr" {
+  name_label = "Local storage"
+}
+
+# Import a raw image
+resource "xenserver_vdi_import" "raw" {
+  name_label      = "Test raw VDI"
+  sr_uuid         = data.xenserver_sr.sr.data_items[0].uuid
+  source_file     = "/path/to/disk.raw"
+  source_checksum = filesha256("/path/to/disk.raw")
+}
+
+# Import a qcow2 cloud image, and extend the disk to 20 GiB
+resource "xenserver_vdi_import" "qcow2" {
+  name_label      = "Test cloud image VDI"
+  sr_uuid         = data.xenserver_sr.sr.data_items[0].uuid
+  source_file     = "/path/to/cloud-image.qcow2"
+  source_format   = "qcow2"
+  source_checksum = filesha256("/path/to/cloud-image.qcow2")
+  virtual_size    = 20 * 1024 * 1024 * 1024
+}
\ No newline at end of file
@@ -51,12 +51,24 @@ resource "xenserver_vm" "windows_vm" {
       }
       mac          = "11:22:33:44:55:66"
       network_uuid = data.xenserver_network.network.data_items[1].uuid,
+      locking_mode = "locked"
+      ipv4_allowed = ["192.168.1.10"]
+      mtu          = 1500
     },
   ]
 
   other_config = {
     "tf_created" = "true"
   }
+
+  platform = {
+    "viridian" = "true"
+  }
+  xenstore_data = {
+    "vm-data/hostname" = "windows-vm"
+  }
+  has_vendor_device = true
+  tags              = ["windows", "terraform"]
 }
 
 # Create a Windows 11 VM that is copy from the custom template
@@ -82,6 +94,8 @@ resource "xenserver_vm" "linux_vm" {
   template_name    = "CustomTemplate"
   static_mem_max   = 4 * 1024 * 1024 * 1024
   vcpus            = 4
+  vcpus_max        = 8 # allow hot adding VCPUs up to 8 when the VM is running
+  vcpu_weight      = 512
   check_ip_timeout = 60 * 5
 
   # Don't need to set up a hard drive if the custom template includes it
@@ -0,0 +1,51 @@
+data "xenserver_network" "network" {}
+
+resource "xenserver_vm" "db" {
+  name_label     = "Database VM"
+  template_name  = "Windows 11"
+  static_mem_max = 4 * 1024 * 1024 * 1024
+  vcpus          = 2
+
+  network_interface = [
+    {
+      network_uuid = data.xenserver_network.network.data_items[0].uuid,
+      device       = "0"
+    },
+  ]
+}
+
+resource "xenserver_vm" "web" {
+  name_label     = "Web VM"
+  template_name  = "Windows 11"
+  static_mem_max = 4 * 1024 * 1024 * 1024
+  vcpus          = 2
+
+  network_interface = [
+    {
+      network_uuid = data.xenserver_network.network.data_items[0].uuid,
+      device       = "0"
+    },
+  ]
+}
+
+# The database VM starts first, the web VM starts 30 seconds later.
+# On shutdown, the web VM is shut down first.
+resource "xenserver_vm_appliance" "app" {
+  name_label       = "Web application"
+  name_description = "A multi-tier web application"
+
+  vms = [
+    {
+      vm_uuid     = xenserver_vm.db.uuid
+      order       = 0
+      start_delay = 30
+    },
+    {
+      vm_uuid        = xenserver_vm.web.uuid
+      order          = 1
+      shutdown_delay = 10
+    },
+  ]
+
+  power_state = "running"
+}
\ No newline at end of file
@@ -0,0 +1,357 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &bondResource{}
+	_ resource.ResourceWithConfigure   = &bondResource{}
+	_ resource.ResourceWithImportState = &bondResource{}
+)
+
+func NewBondResource() resource.Resource {
+	return &bondResource{}
+}
+
+// bondResource defines the resource implementation.
+type bondResource struct {
+	session *xenapi.Session
+}
+
+func (r *bondResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bond"
+}
+
+func (r *bondResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a NIC bond resource. The bond is created with the same NICs on every host in the pool, and a network is created to pass traffic over the bond.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the bond network.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the bond network, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"nics": schema.SetAttribute{
+				MarkdownDescription: "The member NICs of the bond, for example, `[\"NIC 0\", \"NIC 1\"]`. A bond can have 2 to 4 member NICs." + "<br />" +
+					"The NIC on target XenServer environment can be found by the `xenserver_nic` data-source with `network_type = \"bond\"`." +
+					"\n\n-> **Note:** `nics` is not allowed to be updated.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.Set{
+					setvalidator.SizeBetween(2, 4),
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(
+						regexp.MustCompile(`^NIC [0-9]+$`),
+						`must be a physical NIC, eg. "NIC 0"`,
+					)),
+				},
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "The bonding mode, default to be `\"balance-slb\"`." + "<br />" +
+					"This value can be one of [`\"balance-slb\", \"active-backup\", \"lacp\"`].",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(string(xenapi.BondModeBalanceSlb)),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(xenapi.BondModeBalanceSlb),
+						string(xenapi.BondModeActiveBackup),
+						string(xenapi.BondModeLacp),
+					),
+				},
+			},
+			"hashing_algorithm": schema.StringAttribute{
+				MarkdownDescription: "The hashing algorithm of the LACP bond, it can be one of [`\"src_mac\", \"tcpudp_ports\"`]. Only supported when `mode` is `\"lacp\"`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("src_mac", "tcpudp_ports"),
+				},
+			},
+			"lacp_timeout": schema.StringAttribute{
+				MarkdownDescription: "The LACP timeout of the bond, it can be one of [`\"slow\", \"fast\"`]. Only supported when `mode` is `\"lacp\"`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("slow", "fast"),
+				},
+			},
+			"mtu": schema.Int32Attribute{
+				MarkdownDescription: "The MTU of the bond network, default to be `1500`. The minimum value this attribute can be set is `68`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1500),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(68),
+				},
+			},
+			"nic": schema.StringAttribute{
+				MarkdownDescription: "The NIC name of the bond, for example, `\"Bond 0+1\"`. It can be used as the `nic` of `xenserver_network_vlan`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the bond network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the bond network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *bondResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *bondResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data bondResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := bondResourceModelCheck(data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error create xenserver_bond configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Creating bond network...")
+	networkRecord := xenapi.NetworkRecord{
+		NameLabel:       data.NameLabel.ValueString(),
+		NameDescription: data.NameDescription.ValueString(),
+		MTU:             int(data.MTU.ValueInt32()),
+		Managed:         true,
+		OtherConfig:     map[string]string{},
+	}
+	networkRef, err := xenapi.Network.Create(r.session, networkRecord)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create bond network",
+			err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Creating bond...")
+	err = createBonds(ctx, r.session, networkRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create bond",
+			err.Error(),
+		)
+		err = cleanupBondResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up bond resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	networkRecord, err = xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		err = cleanupBondResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up bond resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateBondResourceModelComputed(ctx, r.session, networkRef, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of bondResourceModel",
+			err.Error(),
+		)
+		err = cleanupBondResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up bond resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	tflog.Debug(ctx, "Bond created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *bondResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data bondResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateBondResourceModel(ctx, r.session, networkRef, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of bondResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *bondResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state bondResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := bondResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_bond configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	networkRef, err := xenapi.Network.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = bondResourceModelUpdate(r.session, networkRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update bond resource",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateBondResourceModelComputed(ctx, r.session, networkRef, networkRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of bondResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bondResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data bondResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting bond...")
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupBondResource(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete bond resource",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Bond deleted")
+}
+
+func (r *bondResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
@@ -0,0 +1,86 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccBondResourceConfig(name_label string, nics string, mode string, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_bond" "test_bond" {
+	name_label = "%s"
+	nics       = %s
+	mode       = "%s"
+	%s
+}
+`, name_label, nics, mode, extra_config)
+}
+
+func TestAccBondResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Testing with expected failure
+			{
+				Config:      providerConfig + testAccBondResourceConfig("test bond network", `["NIC 2"]`, "balance-slb", ""),
+				ExpectError: regexp.MustCompile(`Attribute nics set must contain at least 2 elements`),
+			},
+			{
+				Config:      providerConfig + testAccBondResourceConfig("test bond network", `["NIC 2", "Bond 0+1"]`, "balance-slb", ""),
+				ExpectError: regexp.MustCompile(`must be a physical NIC, eg. "NIC 0"`),
+			},
+			{
+				Config:      providerConfig + testAccBondResourceConfig("test bond network", `["NIC 2", "NIC 3"]`, "invalid", ""),
+				ExpectError: regexp.MustCompile(`Attribute mode value must be one of`),
+			},
+			{
+				Config:      providerConfig + testAccBondResourceConfig("test bond network", `["NIC 2", "NIC 3"]`, "active-backup", `hashing_algorithm = "src_mac"`),
+				ExpectError: regexp.MustCompile(`only supported when "mode" is "lacp"`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccBondResourceConfig("test bond network", `["NIC 2", "NIC 3"]`, "active-backup", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "name_label", "test bond network"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "nics.#", "2"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "mode", "active-backup"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "mtu", "1500"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "nic", "Bond 2+3"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_bond.test_bond", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_bond.test_bond",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			{
+				Config:      providerConfig + testAccBondResourceConfig("test bond network", `["NIC 2", "NIC 4"]`, "active-backup", ""),
+				ExpectError: regexp.MustCompile(`"nics" doesn't expected to be updated`),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccBondResourceConfig("test bond network 2", `["NIC 2", "NIC 3"]`, "lacp", `
+	hashing_algorithm = "tcpudp_ports"
+	lacp_timeout      = "fast"
+	mtu               = 9000`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "name_label", "test bond network 2"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "mode", "lacp"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "hashing_algorithm", "tcpudp_ports"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "lacp_timeout", "fast"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "mtu", "9000"),
+					resource.TestCheckResourceAttr("xenserver_bond.test_bond", "nic", "Bond 2+3"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
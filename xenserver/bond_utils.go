@@ -0,0 +1,248 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+type bondResourceModel struct {
+	NameLabel        types.String `tfsdk:"name_label"`
+	NameDescription  types.String `tfsdk:"name_description"`
+	NICs             types.Set    `tfsdk:"nics"`
+	Mode             types.String `tfsdk:"mode"`
+	HashingAlgorithm types.String `tfsdk:"hashing_algorithm"`
+	LACPTimeout      types.String `tfsdk:"lacp_timeout"`
+	MTU              types.Int32  `tfsdk:"mtu"`
+	NIC              types.String `tfsdk:"nic"`
+	UUID             types.String `tfsdk:"uuid"`
+	ID               types.String `tfsdk:"id"`
+}
+
+const (
+	bondPropertyHashingAlgorithm = "hashing_algorithm"
+	bondPropertyLACPTime         = "lacp-time"
+)
+
+func getBondNICsFromPlan(ctx context.Context, data bondResourceModel) ([]string, error) {
+	nics := []string{}
+	diags := data.NICs.ElementsAs(ctx, &nics, false)
+	if diags.HasError() {
+		return nics, errors.New("unable to access bond nics")
+	}
+	slices.Sort(nics)
+	return nics, nil
+}
+
+func getBondProperties(data bondResourceModel) map[string]string {
+	properties := make(map[string]string)
+	if !data.HashingAlgorithm.IsNull() && !data.HashingAlgorithm.IsUnknown() {
+		properties[bondPropertyHashingAlgorithm] = data.HashingAlgorithm.ValueString()
+	}
+	if !data.LACPTimeout.IsNull() && !data.LACPTimeout.IsUnknown() {
+		properties[bondPropertyLACPTime] = data.LACPTimeout.ValueString()
+	}
+	return properties
+}
+
+// getBondMemberPIFs returns the physical PIFs of the NICs on each host, the NICs should not be bonded already
+func getBondMemberPIFs(session *xenapi.Session, nics []string) (map[xenapi.HostRef][]xenapi.PIFRef, error) {
+	members := make(map[xenapi.HostRef][]xenapi.PIFRef)
+	devices := []string{}
+	for _, nic := range nics {
+		devices = append(devices, "eth"+strings.Split(nic, " ")[1])
+	}
+	pifRecords, err := xenapi.PIF.GetAllRecords(session)
+	if err != nil {
+		return members, errors.New(err.Error())
+	}
+	for pifRef, pifRecord := range pifRecords {
+		if !pifRecord.Physical || !slices.Contains(devices, pifRecord.Device) {
+			continue
+		}
+		if string(pifRecord.BondSlaveOf) != "OpaqueRef:NULL" {
+			return members, fmt.Errorf("%s on host is already a member of a bond", pifRecord.Device)
+		}
+		members[pifRecord.Host] = append(members[pifRecord.Host], pifRef)
+	}
+	hostRefs, err := xenapi.Host.GetAll(session)
+	if err != nil {
+		return members, errors.New(err.Error())
+	}
+	for _, hostRef := range hostRefs {
+		if len(members[hostRef]) != len(devices) {
+			hostname, err := xenapi.Host.GetHostname(session, hostRef)
+			if err != nil {
+				return members, errors.New(err.Error())
+			}
+			return members, fmt.Errorf("unable to find all the NICs %s on host %s", strings.Join(nics, ", "), hostname)
+		}
+	}
+	return members, nil
+}
+
+// createBonds creates the bond with the same NICs on every host in the pool
+func createBonds(ctx context.Context, session *xenapi.Session, networkRef xenapi.NetworkRef, data bondResourceModel) error {
+	nics, err := getBondNICsFromPlan(ctx, data)
+	if err != nil {
+		return err
+	}
+	members, err := getBondMemberPIFs(session, nics)
+	if err != nil {
+		return err
+	}
+	properties := getBondProperties(data)
+	for _, pifRefs := range members {
+		_, err = xenapi.Bond.Create(session, networkRef, pifRefs, "", xenapi.BondMode(data.Mode.ValueString()), properties)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+func getBondRefsFromNetwork(session *xenapi.Session, networkRef xenapi.NetworkRef) ([]xenapi.BondRef, error) {
+	var bondRefs []xenapi.BondRef
+	pifRefs, err := xenapi.Network.GetPIFs(session, networkRef)
+	if err != nil {
+		return bondRefs, errors.New(err.Error())
+	}
+	for _, pifRef := range pifRefs {
+		bondMasterOf, err := xenapi.PIF.GetBondMasterOf(session, pifRef)
+		if err != nil {
+			return bondRefs, errors.New(err.Error())
+		}
+		bondRefs = append(bondRefs, bondMasterOf...)
+	}
+	return bondRefs, nil
+}
+
+func updateBondResourceModel(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, record xenapi.NetworkRecord, data *bondResourceModel) error {
+	data.NameLabel = types.StringValue(record.NameLabel)
+	return updateBondResourceModelComputed(ctx, session, ref, record, data)
+}
+
+func updateBondResourceModelComputed(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, record xenapi.NetworkRecord, data *bondResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	mtu, err := ToInt32(record.MTU)
+	if err != nil {
+		return err
+	}
+	data.MTU = types.Int32Value(mtu)
+
+	bondRefs, err := getBondRefsFromNetwork(session, ref)
+	if err != nil {
+		return err
+	}
+	if len(bondRefs) == 0 {
+		return errors.New("unable to find bond on network " + record.NameLabel)
+	}
+	bondRecord, err := xenapi.Bond.GetRecord(session, bondRefs[0])
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	bondSlaveDevices, err := getBondSlaveDevices(session, bondRecord.Slaves)
+	if err != nil {
+		return err
+	}
+	data.NIC = types.StringValue(getNICNameForBondDevices(bondSlaveDevices))
+	nics, diags := types.SetValueFrom(ctx, types.StringType, getNICsNameForDevices(bondSlaveDevices, "NIC"))
+	if diags.HasError() {
+		return errors.New("unable to update data for bond nics")
+	}
+	data.NICs = nics
+	data.Mode = types.StringValue(string(bondRecord.Mode))
+
+	// only refresh the properties which are managed by terraform
+	if !data.HashingAlgorithm.IsNull() {
+		data.HashingAlgorithm = types.StringValue(bondRecord.Properties[bondPropertyHashingAlgorithm])
+	}
+	if !data.LACPTimeout.IsNull() {
+		data.LACPTimeout = types.StringValue(bondRecord.Properties[bondPropertyLACPTime])
+	}
+
+	return nil
+}
+
+func bondResourceModelCheck(data bondResourceModel) error {
+	if data.Mode.ValueString() != string(xenapi.BondModeLacp) && len(getBondProperties(data)) > 0 {
+		return errors.New(`"hashing_algorithm" and "lacp_timeout" are only supported when "mode" is "lacp"`)
+	}
+	return nil
+}
+
+func bondResourceModelUpdateCheck(data bondResourceModel, dataState bondResourceModel) error {
+	if !data.NICs.Equal(dataState.NICs) {
+		return errors.New(`"nics" doesn't expected to be updated`)
+	}
+	return bondResourceModelCheck(data)
+}
+
+func bondResourceModelUpdate(session *xenapi.Session, ref xenapi.NetworkRef, data bondResourceModel) error {
+	err := xenapi.Network.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetMTU(session, ref, int(data.MTU.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	bondRefs, err := getBondRefsFromNetwork(session, ref)
+	if err != nil {
+		return err
+	}
+	properties := getBondProperties(data)
+	for _, bondRef := range bondRefs {
+		bondRecord, err := xenapi.Bond.GetRecord(session, bondRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if string(bondRecord.Mode) != data.Mode.ValueString() {
+			err = xenapi.Bond.SetMode(session, bondRef, xenapi.BondMode(data.Mode.ValueString()))
+			if err != nil {
+				return errors.New(err.Error())
+			}
+		}
+		for name, value := range properties {
+			if bondRecord.Properties[name] == value {
+				continue
+			}
+			err = xenapi.Bond.SetProperty(session, bondRef, name, value)
+			if err != nil {
+				return errors.New(err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func cleanupBondResource(session *xenapi.Session, ref xenapi.NetworkRef) error {
+	bondRefs, err := getBondRefsFromNetwork(session, ref)
+	if err != nil {
+		return err
+	}
+	for _, bondRef := range bondRefs {
+		err = xenapi.Bond.Destroy(session, bondRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	err = xenapi.Network.Destroy(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &clusterResource{}
+	_ resource.ResourceWithConfigure   = &clusterResource{}
+	_ resource.ResourceWithImportState = &clusterResource{}
+	_ resource.ResourceWithModifyPlan  = &clusterResource{}
+)
+
+func NewClusterResource() resource.Resource {
+	return &clusterResource{}
+}
+
+// clusterResource defines the resource implementation.
+type clusterResource struct {
+	session *xenapi.Session
+}
+
+func (r *clusterResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster"
+}
+
+func (r *clusterResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a pool clustering resource. Clustering is required by the thin-provisioned shared block storage, the `gfs2` storage repository. All the hosts in the pool are added to the cluster, the cluster hosts of the hosts which join the pool later are created in the next apply." +
+			"\n\n-> **Note:** A pool can only have one cluster. The clustered SRs must be destroyed before the cluster, use `depends_on` in the SR resources to ensure the order.",
+		Attributes: map[string]schema.Attribute{
+			"network_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the network used by the cluster to communicate, the PIF of the network on each cluster host should have an IP address." +
+					"\n\n-> **Note:** `network_uuid` is not allowed to be updated.",
+				Required: true,
+			},
+			"cluster_stack": schema.StringAttribute{
+				MarkdownDescription: "The cluster stack, default to be `\"corosync\"`." +
+					"\n\n-> **Note:** `cluster_stack` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(clusterDefaultStack),
+				Validators: []validator.String{
+					stringvalidator.OneOf(clusterDefaultStack),
+				},
+			},
+			"token_timeout": schema.Float64Attribute{
+				MarkdownDescription: "The corosync token timeout in seconds, default to be `20`." +
+					"\n\n-> **Note:** `token_timeout` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  float64default.StaticFloat64(clusterDefaultTokenTimeout),
+				Validators: []validator.Float64{
+					float64validator.AtLeast(1),
+				},
+			},
+			"token_timeout_coefficient": schema.Float64Attribute{
+				MarkdownDescription: "The corosync token timeout coefficient in seconds, default to be `1`." +
+					"\n\n-> **Note:** `token_timeout_coefficient` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  float64default.StaticFloat64(clusterDefaultTokenTimeoutCoefficient),
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.65),
+				},
+			},
+			"pool_auto_join": schema.BoolAttribute{
+				MarkdownDescription: "True if the hosts joining the pool automatically join the cluster, it's `true` for the cluster created by the resource.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cluster_hosts": schema.MapNestedAttribute{
+				MarkdownDescription: "The cluster host on each host, the key is the host UUID.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the cluster host.",
+							Computed:            true,
+						},
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "True if the cluster host is enabled.",
+							Computed:            true,
+						},
+						"joined": schema.BoolAttribute{
+							MarkdownDescription: "True if the host has joined the cluster.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the cluster.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the cluster.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *clusterResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *clusterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data clusterResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating cluster...")
+	clusterRef, err := createCluster(r.session, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create cluster",
+			err.Error(),
+		)
+		return
+	}
+	err = updateClusterHosts(r.session, clusterRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to add hosts to cluster",
+			err.Error(),
+		)
+		err = cleanupClusterResource(r.session, clusterRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up cluster resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	clusterRecord, err := xenapi.Cluster.GetRecord(r.session, clusterRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get cluster record",
+			err.Error(),
+		)
+		err = cleanupClusterResource(r.session, clusterRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up cluster resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateClusterResourceModelComputed(ctx, r.session, clusterRef, clusterRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of clusterResourceModel",
+			err.Error(),
+		)
+		err = cleanupClusterResource(r.session, clusterRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up cluster resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	tflog.Debug(ctx, "Cluster created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read data from State, retrieve the resource's information, update to State
+// terraform import
+func (r *clusterResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data clusterResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterRef, err := xenapi.Cluster.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get cluster ref",
+			err.Error(),
+		)
+		return
+	}
+	clusterRecord, err := xenapi.Cluster.GetRecord(r.session, clusterRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get cluster record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateClusterResourceModel(ctx, r.session, clusterRef, clusterRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of clusterResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *clusterResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state clusterResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := clusterResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_cluster configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	clusterRef, err := xenapi.Cluster.GetByUUID(r.session, state.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get cluster ref",
+			err.Error(),
+		)
+		return
+	}
+	err = updateClusterHosts(r.session, clusterRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update cluster hosts",
+			err.Error(),
+		)
+		return
+	}
+	clusterRecord, err := xenapi.Cluster.GetRecord(r.session, clusterRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get cluster record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateClusterResourceModelComputed(ctx, r.session, clusterRef, clusterRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of clusterResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// ModifyPlan plans an update to add the cluster hosts when hosts are added to the pool or the cluster hosts are disabled
+func (r *clusterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.session == nil {
+		return
+	}
+	var plan, state clusterResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.NetworkUUID.IsUnknown() {
+		return
+	}
+
+	changed, err := isClusterHostsChanged(ctx, r.session, plan, state.ClusterHosts)
+	if err != nil || !changed {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("cluster_hosts"), types.MapUnknown(types.ObjectType{AttrTypes: clusterHostModelAttrTypes}))...)
+}
+
+func (r *clusterResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data clusterResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterRef, err := xenapi.Cluster.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get cluster ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupClusterResource(r.session, clusterRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete cluster",
+			err.Error(),
+		)
+		return
+	}
+}
+
+func (r *clusterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
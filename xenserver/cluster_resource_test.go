@@ -0,0 +1,51 @@
+package xenserver
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccClusterResourceConfig(extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_cluster" "test_cluster" {
+	network_uuid = "%s"
+	%s
+}
+`, os.Getenv("CLUSTER_NETWORK_UUID"), extra_config)
+}
+
+func TestAccClusterResource(t *testing.T) {
+	if os.Getenv("CLUSTER_NETWORK_UUID") == "" {
+		t.Skip("Skipping TestAccClusterResource test due to CLUSTER_NETWORK_UUID not set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccClusterResourceConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_cluster.test_cluster", "network_uuid", os.Getenv("CLUSTER_NETWORK_UUID")),
+					resource.TestCheckResourceAttr("xenserver_cluster.test_cluster", "cluster_stack", "corosync"),
+					resource.TestCheckResourceAttr("xenserver_cluster.test_cluster", "token_timeout", "20"),
+					resource.TestCheckResourceAttr("xenserver_cluster.test_cluster", "token_timeout_coefficient", "1"),
+					resource.TestCheckResourceAttr("xenserver_cluster.test_cluster", "pool_auto_join", "true"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_cluster.test_cluster", "uuid"),
+					resource.TestCheckResourceAttrSet("xenserver_cluster.test_cluster", "cluster_hosts.%"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "xenserver_cluster.test_cluster",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,349 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+const (
+	clusterDefaultStack                   = "corosync"
+	clusterDefaultTokenTimeout            = 20.0
+	clusterDefaultTokenTimeoutCoefficient = 1.0
+)
+
+type clusterResourceModel struct {
+	NetworkUUID             types.String  `tfsdk:"network_uuid"`
+	ClusterStack            types.String  `tfsdk:"cluster_stack"`
+	TokenTimeout            types.Float64 `tfsdk:"token_timeout"`
+	TokenTimeoutCoefficient types.Float64 `tfsdk:"token_timeout_coefficient"`
+	PoolAutoJoin            types.Bool    `tfsdk:"pool_auto_join"`
+	ClusterHosts            types.Map     `tfsdk:"cluster_hosts"`
+	UUID                    types.String  `tfsdk:"uuid"`
+	ID                      types.String  `tfsdk:"id"`
+}
+
+type clusterHostModel struct {
+	UUID    types.String `tfsdk:"uuid"`
+	Enabled types.Bool   `tfsdk:"enabled"`
+	Joined  types.Bool   `tfsdk:"joined"`
+}
+
+var clusterHostModelAttrTypes = map[string]attr.Type{
+	"uuid":    types.StringType,
+	"enabled": types.BoolType,
+	"joined":  types.BoolType,
+}
+
+// getClusterPIFs returns the PIFs of the network on all the hosts in the pool keyed by the host UUID,
+// gfs2 SR requires all the hosts in the pool to be in the cluster as its PBDs are plugged on every host
+func getClusterPIFs(session *xenapi.Session, networkUUID string) (map[string]xenapi.PIFRef, error) {
+	clusterPIFs := make(map[string]xenapi.PIFRef)
+	networkRef, err := xenapi.Network.GetByUUID(session, networkUUID)
+	if err != nil {
+		return clusterPIFs, errors.New(err.Error())
+	}
+	pifRefs, err := xenapi.Network.GetPIFs(session, networkRef)
+	if err != nil {
+		return clusterPIFs, errors.New(err.Error())
+	}
+	networkPIFs := make(map[string]xenapi.PIFRef)
+	for _, pifRef := range pifRefs {
+		hostRef, err := xenapi.PIF.GetHost(session, pifRef)
+		if err != nil {
+			return clusterPIFs, errors.New(err.Error())
+		}
+		hostUUID, err := xenapi.Host.GetUUID(session, hostRef)
+		if err != nil {
+			return clusterPIFs, errors.New(err.Error())
+		}
+		networkPIFs[hostUUID] = pifRef
+	}
+
+	hostRecords, err := xenapi.Host.GetAllRecords(session)
+	if err != nil {
+		return clusterPIFs, errors.New(err.Error())
+	}
+	for _, hostRecord := range hostRecords {
+		pifRef, ok := networkPIFs[hostRecord.UUID]
+		if !ok {
+			return clusterPIFs, fmt.Errorf("unable to find the PIF of network %s on host %s", networkUUID, hostRecord.NameLabel)
+		}
+		clusterPIFs[hostRecord.UUID] = pifRef
+	}
+	return clusterPIFs, nil
+}
+
+// checkClusterPIFs returns error if any PIF used by the cluster has no IP address
+func checkClusterPIFs(session *xenapi.Session, clusterPIFs map[string]xenapi.PIFRef) error {
+	for hostUUID, pifRef := range clusterPIFs {
+		ip, err := xenapi.PIF.GetIP(session, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if ip == "" {
+			return fmt.Errorf("the PIF of the cluster network on host %s has no IP address configured", hostUUID)
+		}
+	}
+	return nil
+}
+
+// getClusterHostsByHost returns the cluster hosts of the cluster keyed by the host UUID
+func getClusterHostsByHost(session *xenapi.Session, clusterRef xenapi.ClusterRef) (map[string]xenapi.ClusterHostRecord, error) {
+	clusterHosts := make(map[string]xenapi.ClusterHostRecord)
+	clusterHostRecords, err := xenapi.ClusterHost.GetAllRecords(session)
+	if err != nil {
+		return clusterHosts, errors.New(err.Error())
+	}
+	for _, clusterHostRecord := range clusterHostRecords {
+		if clusterHostRecord.Cluster != clusterRef {
+			continue
+		}
+		hostUUID, err := xenapi.Host.GetUUID(session, clusterHostRecord.Host)
+		if err != nil {
+			return clusterHosts, errors.New(err.Error())
+		}
+		clusterHosts[hostUUID] = clusterHostRecord
+	}
+	return clusterHosts, nil
+}
+
+// createCluster creates the cluster on the coordinator, the other hosts are added by updateClusterHosts
+func createCluster(session *xenapi.Session, data clusterResourceModel) (xenapi.ClusterRef, error) {
+	var clusterRef xenapi.ClusterRef
+	clusterRefs, err := xenapi.Cluster.GetAll(session)
+	if err != nil {
+		return clusterRef, errors.New(err.Error())
+	}
+	if len(clusterRefs) > 0 {
+		return clusterRef, errors.New("the pool already has a cluster, import it instead")
+	}
+	clusterPIFs, err := getClusterPIFs(session, data.NetworkUUID.ValueString())
+	if err != nil {
+		return clusterRef, err
+	}
+	err = checkClusterPIFs(session, clusterPIFs)
+	if err != nil {
+		return clusterRef, err
+	}
+	_, coordinatorUUID, err := getCoordinatorRef(session)
+	if err != nil {
+		return clusterRef, err
+	}
+	clusterRef, err = xenapi.Cluster.Create(session, clusterPIFs[coordinatorUUID], data.ClusterStack.ValueString(), true, data.TokenTimeout.ValueFloat64(), data.TokenTimeoutCoefficient.ValueFloat64())
+	if err != nil {
+		return clusterRef, errors.New(err.Error())
+	}
+	return clusterRef, nil
+}
+
+// updateClusterHosts adds the hosts in the pool which aren't in the cluster yet,
+// and removes the cluster hosts of the hosts which are not in the pool any more.
+func updateClusterHosts(session *xenapi.Session, clusterRef xenapi.ClusterRef, data clusterResourceModel) error {
+	clusterPIFs, err := getClusterPIFs(session, data.NetworkUUID.ValueString())
+	if err != nil {
+		return err
+	}
+	clusterHosts, err := getClusterHostsByHost(session, clusterRef)
+	if err != nil {
+		return err
+	}
+	for hostUUID, pifRef := range clusterPIFs {
+		clusterHostRecord, ok := clusterHosts[hostUUID]
+		if ok {
+			if !clusterHostRecord.Enabled {
+				clusterHostRef, err := xenapi.ClusterHost.GetByUUID(session, clusterHostRecord.UUID)
+				if err != nil {
+					return errors.New(err.Error())
+				}
+				err = xenapi.ClusterHost.Enable(session, clusterHostRef)
+				if err != nil {
+					return errors.New(err.Error())
+				}
+			}
+			continue
+		}
+		err = checkClusterPIFs(session, map[string]xenapi.PIFRef{hostUUID: pifRef})
+		if err != nil {
+			return err
+		}
+		hostRef, err := xenapi.Host.GetByUUID(session, hostUUID)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		_, err = xenapi.ClusterHost.Create(session, clusterRef, hostRef, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	for hostUUID, clusterHostRecord := range clusterHosts {
+		if _, ok := clusterPIFs[hostUUID]; ok {
+			continue
+		}
+		clusterHostRef, err := xenapi.ClusterHost.GetByUUID(session, clusterHostRecord.UUID)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		err = xenapi.ClusterHost.Destroy(session, clusterHostRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// isClusterHostsChanged returns true if the hosts in the pool are different from the hosts in clusterHosts
+func isClusterHostsChanged(ctx context.Context, session *xenapi.Session, data clusterResourceModel, clusterHosts types.Map) (bool, error) {
+	clusterPIFs, err := getClusterPIFs(session, data.NetworkUUID.ValueString())
+	if err != nil {
+		return false, err
+	}
+	clusterHostsMap := make(map[string]clusterHostModel)
+	diags := clusterHosts.ElementsAs(ctx, &clusterHostsMap, false)
+	if diags.HasError() {
+		return false, errors.New("unable to access cluster cluster_hosts")
+	}
+	if len(clusterPIFs) != len(clusterHostsMap) {
+		return true, nil
+	}
+	for hostUUID := range clusterPIFs {
+		clusterHost, ok := clusterHostsMap[hostUUID]
+		if !ok || !clusterHost.Enabled.ValueBool() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func updateClusterResourceModel(ctx context.Context, session *xenapi.Session, ref xenapi.ClusterRef, record xenapi.ClusterRecord, data *clusterResourceModel) error {
+	networkRef, err := xenapi.Cluster.GetNetwork(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	networkUUID, err := xenapi.Network.GetUUID(session, networkRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	data.NetworkUUID = types.StringValue(networkUUID)
+	data.ClusterStack = types.StringValue(record.ClusterStack)
+	data.TokenTimeout = types.Float64Value(record.TokenTimeout)
+	data.TokenTimeoutCoefficient = types.Float64Value(record.TokenTimeoutCoefficient)
+
+	return updateClusterResourceModelComputed(ctx, session, ref, record, data)
+}
+
+func updateClusterResourceModelComputed(ctx context.Context, session *xenapi.Session, ref xenapi.ClusterRef, record xenapi.ClusterRecord, data *clusterResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.PoolAutoJoin = types.BoolValue(record.PoolAutoJoin)
+	clusterHostRecords, err := getClusterHostsByHost(session, ref)
+	if err != nil {
+		return err
+	}
+	clusterHosts := make(map[string]clusterHostModel)
+	for hostUUID, clusterHostRecord := range clusterHostRecords {
+		clusterHosts[hostUUID] = clusterHostModel{
+			UUID:    types.StringValue(clusterHostRecord.UUID),
+			Enabled: types.BoolValue(clusterHostRecord.Enabled),
+			Joined:  types.BoolValue(clusterHostRecord.Joined),
+		}
+	}
+	var diags diag.Diagnostics
+	data.ClusterHosts, diags = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: clusterHostModelAttrTypes}, clusterHosts)
+	if diags.HasError() {
+		return errors.New("unable to update data for cluster cluster_hosts")
+	}
+
+	return nil
+}
+
+func clusterResourceModelUpdateCheck(data clusterResourceModel, dataState clusterResourceModel) error {
+	if data.NetworkUUID != dataState.NetworkUUID {
+		return errors.New(`"network_uuid" doesn't expected to be updated`)
+	}
+	if data.ClusterStack != dataState.ClusterStack {
+		return errors.New(`"cluster_stack" doesn't expected to be updated`)
+	}
+	if data.TokenTimeout != dataState.TokenTimeout {
+		return errors.New(`"token_timeout" doesn't expected to be updated`)
+	}
+	if data.TokenTimeoutCoefficient != dataState.TokenTimeoutCoefficient {
+		return errors.New(`"token_timeout_coefficient" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+// getClusteredSRNames returns the names of the clustered SRs which are still attached to any host
+func getClusteredSRNames(session *xenapi.Session) ([]string, error) {
+	var names []string
+	srRecords, err := xenapi.SR.GetAllRecords(session)
+	if err != nil {
+		return names, errors.New(err.Error())
+	}
+	for _, srRecord := range srRecords {
+		if !srRecord.Clustered {
+			continue
+		}
+		for _, pbdRef := range srRecord.PBDs {
+			currentlyAttached, err := xenapi.PBD.GetCurrentlyAttached(session, pbdRef)
+			if err != nil {
+				return names, errors.New(err.Error())
+			}
+			if currentlyAttached {
+				names = append(names, srRecord.NameLabel)
+				break
+			}
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// cleanupClusterResource destroys the cluster and all its cluster hosts, the clustered SRs must be detached first
+func cleanupClusterResource(session *xenapi.Session, ref xenapi.ClusterRef) error {
+	srNames, err := getClusteredSRNames(session)
+	if err != nil {
+		return err
+	}
+	if len(srNames) > 0 {
+		return fmt.Errorf("the cluster is still used by the clustered SR(s) %v, destroy them before the cluster", srNames)
+	}
+	err = xenapi.Cluster.PoolDestroy(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// checkClusterReady returns error if the pool has no cluster or any host isn't an enabled member of it, which is required by gfs2 SR
+func checkClusterReady(session *xenapi.Session) error {
+	clusterRefs, err := xenapi.Cluster.GetAll(session)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if len(clusterRefs) == 0 {
+		return errors.New("gfs2 SR requires the pool to be clustered, create the cluster by xenserver_cluster first")
+	}
+	clusterHosts, err := getClusterHostsByHost(session, clusterRefs[0])
+	if err != nil {
+		return err
+	}
+	hostRecords, err := xenapi.Host.GetAllRecords(session)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	for _, hostRecord := range hostRecords {
+		clusterHost, ok := clusterHosts[hostRecord.UUID]
+		if !ok || !clusterHost.Enabled {
+			return fmt.Errorf("gfs2 SR requires all the hosts in the cluster, host %s isn't an enabled cluster member", hostRecord.NameLabel)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package xenserver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type filterObject struct {
+	Name     types.String `tfsdk:"name"`
+	Operator types.String `tfsdk:"operator"`
+	Value    types.String `tfsdk:"value"`
+}
+
+var filterOperators = []string{"equals", "not_equals", "regex", "contains", "gt", "ge", "lt", "le"}
+
+// getFilterFieldNames returns the tfsdk names of the fields of the data item struct
+func getFilterFieldNames(item any) []string {
+	var names []string
+	itemType := reflect.TypeOf(item)
+	for i := 0; i < itemType.NumField(); i++ {
+		name := itemType.Field(i).Tag.Get("tfsdk")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func filterSchema(item any) schema.ListNestedAttribute {
+	fieldNames := getFilterFieldNames(item)
+	return schema.ListNestedAttribute{
+		MarkdownDescription: "The filters to match the return items, all the filters should be matched." + "<br />" +
+			"The field of a list or set matches when any element matches, the element of a map is compared as `\"<key>=<value>\"`.",
+		Optional: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					MarkdownDescription: "The name of the field in `data_items` to filter on, for example, `\"uuid\"`.",
+					Required:            true,
+					Validators: []validator.String{
+						stringvalidator.OneOf(fieldNames...),
+					},
+				},
+				"operator": schema.StringAttribute{
+					MarkdownDescription: "The operator to compare the field with `value`, default to be `\"equals\"`, for example, `\"equals\"`, `\"not_equals\"`, `\"regex\"`, `\"contains\"`, `\"gt\"`, `\"ge\"`, `\"lt\"`, `\"le\"`." + "<br />" +
+						"`\"gt\"`, `\"ge\"`, `\"lt\"` and `\"le\"` compare the field and `value` as numbers.",
+					Optional: true,
+					Validators: []validator.String{
+						stringvalidator.OneOf(filterOperators...),
+					},
+				},
+				"value": schema.StringAttribute{
+					MarkdownDescription: "The value to compare with, for example, `\"100\"`, `\"true\"`, `\"^eth[0-9]+$\"`.",
+					Required:            true,
+				},
+			},
+		},
+	}
+}
+
+func expectCountSchema() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		MarkdownDescription: "The expected number of the return items, the data source fails when the number doesn't match.",
+		Optional:            true,
+	}
+}
+
+// getFilterFieldValues returns the field value of the data item as strings
+func getFilterFieldValues(item any, name string) ([]string, error) {
+	itemValue := reflect.ValueOf(item)
+	itemType := itemValue.Type()
+	for i := 0; i < itemType.NumField(); i++ {
+		if itemType.Field(i).Tag.Get("tfsdk") != name {
+			continue
+		}
+		value, ok := itemValue.Field(i).Interface().(attr.Value)
+		if !ok {
+			return nil, fmt.Errorf("unable to filter on field %s", name)
+		}
+		return getAttrValueStrings(value), nil
+	}
+	return nil, fmt.Errorf("unable to find field %s", name)
+}
+
+func getAttrValueStrings(value attr.Value) []string {
+	var values []string
+	if value.IsNull() || value.IsUnknown() {
+		return values
+	}
+	switch v := value.(type) {
+	case types.String:
+		values = append(values, v.ValueString())
+	case types.Bool:
+		values = append(values, strconv.FormatBool(v.ValueBool()))
+	case types.Int32:
+		values = append(values, strconv.FormatInt(int64(v.ValueInt32()), 10))
+	case types.Int64:
+		values = append(values, strconv.FormatInt(v.ValueInt64(), 10))
+	case types.Float64:
+		values = append(values, strconv.FormatFloat(v.ValueFloat64(), 'f', -1, 64))
+	case types.List:
+		for _, element := range v.Elements() {
+			values = append(values, getAttrValueStrings(element)...)
+		}
+	case types.Set:
+		for _, element := range v.Elements() {
+			values = append(values, getAttrValueStrings(element)...)
+		}
+	case types.Map:
+		for key, element := range v.Elements() {
+			for _, elementValue := range getAttrValueStrings(element) {
+				values = append(values, key+"="+elementValue)
+			}
+		}
+	default:
+		values = append(values, value.String())
+	}
+	return values
+}
+
+func compareFilterNumber(fieldValue string, filterValue string, operator string) (bool, error) {
+	fieldNumber, err := strconv.ParseFloat(fieldValue, 64)
+	if err != nil {
+		return false, nil
+	}
+	filterNumber, err := strconv.ParseFloat(filterValue, 64)
+	if err != nil {
+		return false, fmt.Errorf("the value %s of operator %s is not a number", filterValue, operator)
+	}
+	switch operator {
+	case "gt":
+		return fieldNumber > filterNumber, nil
+	case "ge":
+		return fieldNumber >= filterNumber, nil
+	case "lt":
+		return fieldNumber < filterNumber, nil
+	default:
+		return fieldNumber <= filterNumber, nil
+	}
+}
+
+func matchFilter(item any, filter filterObject) (bool, error) {
+	fieldValues, err := getFilterFieldValues(item, filter.Name.ValueString())
+	if err != nil {
+		return false, err
+	}
+	operator := "equals"
+	if !filter.Operator.IsNull() {
+		operator = filter.Operator.ValueString()
+	}
+	filterValue := filter.Value.ValueString()
+	if operator == "not_equals" {
+		return !slices.Contains(fieldValues, filterValue), nil
+	}
+	var re *regexp.Regexp
+	if operator == "regex" {
+		re, err = regexp.Compile(filterValue)
+		if err != nil {
+			return false, errors.New("unable to compile regex " + filterValue + ". " + err.Error())
+		}
+	}
+	for _, fieldValue := range fieldValues {
+		var matched bool
+		switch operator {
+		case "equals":
+			matched = fieldValue == filterValue
+		case "regex":
+			matched = re.MatchString(fieldValue)
+		case "contains":
+			matched = strings.Contains(fieldValue, filterValue)
+		case "gt", "ge", "lt", "le":
+			matched, err = compareFilterNumber(fieldValue, filterValue, operator)
+			if err != nil {
+				return false, err
+			}
+		default:
+			return false, fmt.Errorf("unsupported filter operator %s", operator)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchFilters returns true if the data item matches all the filters
+func matchFilters(item any, filters []filterObject) (bool, error) {
+	for _, filter := range filters {
+		matched, err := matchFilter(item, filter)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func checkExpectCount(expectCount types.Int64, count int) error {
+	if !expectCount.IsNull() && expectCount.ValueInt64() != int64(count) {
+		return fmt.Errorf("expected %d item(s), but found %d", expectCount.ValueInt64(), count)
+	}
+	return nil
+}
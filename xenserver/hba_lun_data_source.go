@@ -0,0 +1,167 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+
+	"xenapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &hbaLUNDataSource{}
+	_ datasource.DataSourceWithConfigure = &hbaLUNDataSource{}
+)
+
+// NewHBALUNDataSource is a helper function to simplify the provider implementation.
+func NewHBALUNDataSource() datasource.DataSource {
+	return &hbaLUNDataSource{}
+}
+
+// hbaLUNDataSource is the data source implementation.
+type hbaLUNDataSource struct {
+	session *xenapi.Session
+}
+
+// Metadata returns the data source type name.
+func (d *hbaLUNDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hba_lun"
+}
+
+// Schema defines the schema for the data source.
+func (d *hbaLUNDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides information about the LUNs visible through the Fibre Channel or SAS hardware HBAs of each host.",
+
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the host to probe, all the hosts in the pool are probed when it's not set.",
+				Optional:            true,
+			},
+			"scsi_id": schema.StringAttribute{
+				MarkdownDescription: "The SCSI ID of the LUN.",
+				Optional:            true,
+			},
+			"data_items": schema.ListNestedAttribute{
+				MarkdownDescription: "The return items of LUNs.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the host which the LUN is visible on.",
+							Computed:            true,
+						},
+						"scsi_id": schema.StringAttribute{
+							MarkdownDescription: "The SCSI ID of the LUN.",
+							Computed:            true,
+						},
+						"path": schema.StringAttribute{
+							MarkdownDescription: "The device path of the LUN on the host.",
+							Computed:            true,
+						},
+						"vendor": schema.StringAttribute{
+							MarkdownDescription: "The vendor of the LUN.",
+							Computed:            true,
+						},
+						"serial": schema.StringAttribute{
+							MarkdownDescription: "The serial number of the LUN.",
+							Computed:            true,
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "The size of the LUN in bytes.",
+							Computed:            true,
+						},
+						"adapter": schema.StringAttribute{
+							MarkdownDescription: "The HBA adapter which the LUN is visible through.",
+							Computed:            true,
+						},
+						"lun": schema.StringAttribute{
+							MarkdownDescription: "The LUN number.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *hbaLUNDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.session = providerData.session
+}
+
+func (d *hbaLUNDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data hbaLUNDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostRecords, err := xenapi.Host.GetAllRecords(d.session)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host records",
+			err.Error(),
+		)
+		return
+	}
+
+	var lunItems []hbaLUNItemData
+	for hostRef, hostRecord := range hostRecords {
+		if !data.Host.IsNull() && hostRecord.UUID != data.Host.ValueString() {
+			continue
+		}
+		devices, err := probeHBADevices(d.session, hostRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to probe HBA LUNs on host "+hostRecord.NameLabel,
+				err.Error(),
+			)
+			return
+		}
+		for _, device := range devices {
+			var lunData hbaLUNItemData
+			err = updateHBALUNItemData(hostRecord.UUID, device, &lunData)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to update HBA LUN data",
+					err.Error(),
+				)
+				return
+			}
+			if !data.SCSIid.IsNull() && lunData.SCSIid.ValueString() != data.SCSIid.ValueString() {
+				continue
+			}
+			lunItems = append(lunItems, lunData)
+		}
+	}
+
+	sort.Slice(lunItems, func(i, j int) bool {
+		if lunItems[i].Host.ValueString() != lunItems[j].Host.ValueString() {
+			return lunItems[i].Host.ValueString() < lunItems[j].Host.ValueString()
+		}
+		return lunItems[i].SCSIid.ValueString() < lunItems[j].SCSIid.ValueString()
+	})
+	data.DataItems = lunItems
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
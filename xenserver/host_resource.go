@@ -0,0 +1,352 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &hostResource{}
+	_ resource.ResourceWithConfigure   = &hostResource{}
+	_ resource.ResourceWithImportState = &hostResource{}
+	_ resource.ResourceWithModifyPlan  = &hostResource{}
+)
+
+func NewHostResource() resource.Resource {
+	return &hostResource{}
+}
+
+// hostResource defines the resource implementation.
+type hostResource struct {
+	session *xenapi.Session
+}
+
+func (r *hostResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host"
+}
+
+func (r *hostResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Host configuration resource which is used to update the settings of an existing host, the settings not set in the configuration are kept as they are on the host. \n\n Noted that no new host will be deployed when `terraform apply` is executed. Additionally, when it comes to `terraform destroy`, it actually has no effect on this resource.",
+		Attributes: map[string]schema.Attribute{
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the host.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the host.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the host.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: "The hostname of the host, it's applied to the running host without reboot.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ntp_mode": schema.StringAttribute{
+				MarkdownDescription: "The NTP mode of the host, for example, `\"dhcp\"`, `\"custom\"`, `\"default\"`, `\"disabled\"`." + "<br />" +
+					"`\"dhcp\"` uses the NTP servers assigned by DHCP, `\"custom\"` uses the servers in `ntp_servers`, `\"default\"` uses the default NTP servers provided by XenServer.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("dhcp", "custom", "default", "disabled"),
+				},
+			},
+			"ntp_servers": schema.ListAttribute{
+				MarkdownDescription: "The custom NTP servers of the host, only supported when `ntp_mode` is `\"custom\"`. The servers are cleared when `ntp_mode` is not `\"custom\"` and `ntp_servers` is not set.",
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"timezone": schema.StringAttribute{
+				MarkdownDescription: "The timezone of the host, for example, `\"UTC\"`, `\"Europe/London\"`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"syslog_destination": schema.StringAttribute{
+				MarkdownDescription: "The remote syslog server which the host sends the logs to, set to `\"\"` to store the logs on the local disk only.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ssh_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the SSH service is enabled on the host.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ssh_enabled_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The duration in seconds after which the SSH service is disabled automatically, `0` means never.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"console_idle_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The idle duration in seconds after which the console session is logged out, `0` means never.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"other_config": schema.MapAttribute{
+				MarkdownDescription: "The additional configuration of the host, default to be `{}`. Only the keys set by terraform are managed, the other keys on the host are kept.",
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.NoneOf(hostTFOtherConfigKeysField)),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the host.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *hostResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *hostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data hostResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := hostResourceModelCheck(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error create xenserver_host configuration",
+			err.Error(),
+		)
+		return
+	}
+	hostRef, err := xenapi.Host.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host ref",
+			err.Error(),
+		)
+		return
+	}
+	err = hostResourceModelUpdate(ctx, r.session, hostRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update host configuration",
+			err.Error(),
+		)
+		return
+	}
+	hostRecord, err := xenapi.Host.GetRecord(r.session, hostRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateHostResourceModel(ctx, hostRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of hostResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read data from State, retrieve the resource's information, update to State
+// terraform import
+func (r *hostResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data hostResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostRef, err := xenapi.Host.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host ref",
+			err.Error(),
+		)
+		return
+	}
+	hostRecord, err := xenapi.Host.GetRecord(r.session, hostRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateHostResourceModel(ctx, hostRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of hostResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *hostResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan hostResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := hostResourceModelCheck(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_host configuration",
+			err.Error(),
+		)
+		return
+	}
+	hostRef, err := xenapi.Host.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host ref",
+			err.Error(),
+		)
+		return
+	}
+	err = hostResourceModelUpdate(ctx, r.session, hostRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update host configuration",
+			err.Error(),
+		)
+		return
+	}
+	hostRecord, err := xenapi.Host.GetRecord(r.session, hostRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get host record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateHostResourceModel(ctx, hostRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of hostResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// ModifyPlan clears the planned ntp_servers kept from state when ntp_mode isn't "custom" and ntp_servers isn't set
+func (r *hostResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when destroying the resource
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan hostResourceModel
+	var configServers types.List
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ntp_servers"), &configServers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !configServers.IsNull() || !isValueSet(plan.NTPMode) || plan.NTPMode.ValueString() == "custom" {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ntp_servers"), types.ListValueMust(types.StringType, []attr.Value{}))...)
+}
+
+func (r *hostResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "Don't recover the host configuration when destroy resource")
+}
+
+func (r *hostResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
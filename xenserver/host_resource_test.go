@@ -0,0 +1,87 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccHostResourceConfig(extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_host" "host" {
+  is_coordinator = true
+}
+
+resource "xenserver_host" "host" {
+  uuid = data.xenserver_host.host.data_items[0].uuid
+  %s
+}
+`, extra_config)
+}
+
+func TestAccHostResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccHostResourceConfig(`ntp_mode = "manual"`),
+				ExpectError: regexp.MustCompile(`Attribute ntp_mode value must be one of`),
+			},
+			{
+				Config:      providerConfig + testAccHostResourceConfig(`ntp_mode = "dhcp"`+"\n"+`ntp_servers = ["time.example.com"]`),
+				ExpectError: regexp.MustCompile(`"ntp_servers" is only supported when "ntp_mode" is "custom"`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccHostResourceConfig(`
+  name_description = "test host description"
+  timezone         = "UTC"
+  other_config = {
+    "flag" = "1"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_host.host", "name_description", "test host description"),
+					resource.TestCheckResourceAttr("xenserver_host.host", "timezone", "UTC"),
+					resource.TestCheckResourceAttr("xenserver_host.host", "other_config.%", "1"),
+					resource.TestCheckResourceAttr("xenserver_host.host", "other_config.flag", "1"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_host.host", "name_label"),
+					resource.TestCheckResourceAttrSet("xenserver_host.host", "hostname"),
+					resource.TestCheckResourceAttrSet("xenserver_host.host", "ntp_mode"),
+					resource.TestCheckResourceAttrSet("xenserver_host.host", "ssh_enabled"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "xenserver_host.host",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccHostResourceConfig(`
+  name_description = ""
+  ntp_mode         = "custom"
+  ntp_servers      = ["0.pool.ntp.org", "1.pool.ntp.org"]
+  other_config     = {}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_host.host", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_host.host", "ntp_mode", "custom"),
+					resource.TestCheckResourceAttr("xenserver_host.host", "ntp_servers.#", "2"),
+					resource.TestCheckResourceAttr("xenserver_host.host", "ntp_servers.0", "0.pool.ntp.org"),
+					resource.TestCheckResourceAttr("xenserver_host.host", "other_config.%", "0"),
+				),
+			},
+			// Revert changes
+			{
+				Config: providerConfig + testAccHostResourceConfig(`ntp_mode = "default"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_host.host", "ntp_mode", "default"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -3,7 +3,11 @@ package xenserver
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -79,3 +83,204 @@ func updateHostRecordData(ctx context.Context, session *xenapi.Session, record x
 
 	return nil
 }
+
+type hostResourceModel struct {
+	UUID               types.String `tfsdk:"uuid"`
+	NameLabel          types.String `tfsdk:"name_label"`
+	NameDescription    types.String `tfsdk:"name_description"`
+	Hostname           types.String `tfsdk:"hostname"`
+	NTPMode            types.String `tfsdk:"ntp_mode"`
+	NTPServers         types.List   `tfsdk:"ntp_servers"`
+	Timezone           types.String `tfsdk:"timezone"`
+	SyslogDestination  types.String `tfsdk:"syslog_destination"`
+	SSHEnabled         types.Bool   `tfsdk:"ssh_enabled"`
+	SSHEnabledTimeout  types.Int64  `tfsdk:"ssh_enabled_timeout"`
+	ConsoleIdleTimeout types.Int64  `tfsdk:"console_idle_timeout"`
+	OtherConfig        types.Map    `tfsdk:"other_config"`
+	ID                 types.String `tfsdk:"id"`
+}
+
+const (
+	hostNTPModePrefix          = "ntp_mode_"
+	hostLoggingSyslogKey       = "syslog_destination"
+	hostTFOtherConfigKeysField = "tf_other_config_keys"
+)
+
+func isValueSet(value attr.Value) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
+
+func updateHostResourceModel(ctx context.Context, record xenapi.HostRecord, data *hostResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameLabel = types.StringValue(record.NameLabel)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	data.Hostname = types.StringValue(record.Hostname)
+	data.NTPMode = types.StringValue(strings.TrimPrefix(string(record.NtpMode), hostNTPModePrefix))
+	var diags diag.Diagnostics
+	data.NTPServers, diags = types.ListValueFrom(ctx, types.StringType, record.NtpCustomServers)
+	if diags.HasError() {
+		return errors.New("unable to update data for host ntp_servers")
+	}
+	data.Timezone = types.StringValue(record.Timezone)
+	data.SyslogDestination = types.StringValue(record.Logging[hostLoggingSyslogKey])
+	data.SSHEnabled = types.BoolValue(record.SSHEnabled)
+	data.SSHEnabledTimeout = types.Int64Value(int64(record.SSHEnabledTimeout))
+	data.ConsoleIdleTimeout = types.Int64Value(int64(record.ConsoleIdleTimeout))
+	otherConfig, err := getTFManagedMap(ctx, record.OtherConfig, record.OtherConfig[hostTFOtherConfigKeysField])
+	if err != nil {
+		return err
+	}
+	data.OtherConfig = otherConfig
+	return nil
+}
+
+func hostResourceModelCheck(ctx context.Context, data hostResourceModel) error {
+	if !isValueSet(data.NTPServers) || data.NTPMode.IsUnknown() {
+		return nil
+	}
+	servers := []string{}
+	diags := data.NTPServers.ElementsAs(ctx, &servers, false)
+	if diags.HasError() {
+		return errors.New("unable to access host ntp_servers")
+	}
+	if len(servers) > 0 && data.NTPMode.ValueString() != "custom" {
+		return errors.New(`"ntp_servers" is only supported when "ntp_mode" is "custom"`)
+	}
+	return nil
+}
+
+func updateHostNTP(ctx context.Context, session *xenapi.Session, ref xenapi.HostRef, record xenapi.HostRecord, data hostResourceModel) error {
+	servers := []string{}
+	if isValueSet(data.NTPServers) {
+		diags := data.NTPServers.ElementsAs(ctx, &servers, false)
+		if diags.HasError() {
+			return errors.New("unable to access host ntp_servers")
+		}
+	}
+	serversChanged := isValueSet(data.NTPServers) && !slices.Equal(servers, record.NtpCustomServers)
+	// the custom servers should be set before switching to the custom mode, and cleared after switching from it
+	if serversChanged && len(servers) > 0 {
+		err := xenapi.Host.SetNtpCustomServers(session, ref, servers)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if isValueSet(data.NTPMode) && hostNTPModePrefix+data.NTPMode.ValueString() != string(record.NtpMode) {
+		err := xenapi.Host.SetNtpMode(session, ref, xenapi.HostNtpMode(hostNTPModePrefix+data.NTPMode.ValueString()))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if serversChanged && len(servers) == 0 {
+		err := xenapi.Host.SetNtpCustomServers(session, ref, servers)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+func updateHostSSH(session *xenapi.Session, ref xenapi.HostRef, record xenapi.HostRecord, data hostResourceModel) error {
+	if isValueSet(data.SSHEnabled) && data.SSHEnabled.ValueBool() != record.SSHEnabled {
+		var err error
+		if data.SSHEnabled.ValueBool() {
+			err = xenapi.Host.EnableSSH(session, ref)
+		} else {
+			err = xenapi.Host.DisableSSH(session, ref)
+		}
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if isValueSet(data.SSHEnabledTimeout) && int(data.SSHEnabledTimeout.ValueInt64()) != record.SSHEnabledTimeout {
+		err := xenapi.Host.SetSSHEnabledTimeout(session, ref, int(data.SSHEnabledTimeout.ValueInt64()))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if isValueSet(data.ConsoleIdleTimeout) && int(data.ConsoleIdleTimeout.ValueInt64()) != record.ConsoleIdleTimeout {
+		err := xenapi.Host.SetConsoleIdleTimeout(session, ref, int(data.ConsoleIdleTimeout.ValueInt64()))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// hostResourceModelUpdate applies the configured settings which are different from the current host settings
+func hostResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref xenapi.HostRef, data hostResourceModel) error {
+	record, err := xenapi.Host.GetRecord(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	if isValueSet(data.NameLabel) && data.NameLabel.ValueString() != record.NameLabel {
+		err = xenapi.Host.SetNameLabel(session, ref, data.NameLabel.ValueString())
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if isValueSet(data.NameDescription) && data.NameDescription.ValueString() != record.NameDescription {
+		err = xenapi.Host.SetNameDescription(session, ref, data.NameDescription.ValueString())
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if isValueSet(data.Hostname) && data.Hostname.ValueString() != record.Hostname {
+		err = xenapi.Host.SetHostnameLive(session, ref, data.Hostname.ValueString())
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	err = updateHostNTP(ctx, session, ref, record, data)
+	if err != nil {
+		return err
+	}
+	if isValueSet(data.Timezone) && data.Timezone.ValueString() != record.Timezone {
+		err = xenapi.Host.SetTimezone(session, ref, data.Timezone.ValueString())
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if isValueSet(data.SyslogDestination) && data.SyslogDestination.ValueString() != record.Logging[hostLoggingSyslogKey] {
+		logging := make(map[string]string)
+		for key, value := range record.Logging {
+			logging[key] = value
+		}
+		// an empty destination sends the logs to the local disk only
+		delete(logging, hostLoggingSyslogKey)
+		if data.SyslogDestination.ValueString() != "" {
+			logging[hostLoggingSyslogKey] = data.SyslogDestination.ValueString()
+		}
+		err = xenapi.Host.SetLogging(session, ref, logging)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		err = xenapi.Host.SyslogReconfigure(session, ref)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	err = updateHostSSH(session, ref, record, data)
+	if err != nil {
+		return err
+	}
+
+	otherConfig, keys, err := mergeTFManagedMap(ctx, record.OtherConfig, record.OtherConfig[hostTFOtherConfigKeysField], data.OtherConfig)
+	if err != nil {
+		return err
+	}
+	delete(otherConfig, hostTFOtherConfigKeysField)
+	if keys != "" {
+		otherConfig[hostTFOtherConfigKeysField] = keys
+	}
+	if !maps.Equal(otherConfig, record.OtherConfig) {
+		err = xenapi.Host.SetOtherConfig(session, ref, otherConfig)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &iscsiProbeDataSource{}
+	_ datasource.DataSourceWithConfigure = &iscsiProbeDataSource{}
+)
+
+// NewISCSIProbeDataSource is a helper function to simplify the provider implementation.
+func NewISCSIProbeDataSource() datasource.DataSource {
+	return &iscsiProbeDataSource{}
+}
+
+// iscsiProbeDataSource is the data source implementation.
+type iscsiProbeDataSource struct {
+	session *xenapi.Session
+}
+
+// Metadata returns the data source type name.
+func (d *iscsiProbeDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iscsi_probe"
+}
+
+// Schema defines the schema for the data source.
+func (d *iscsiProbeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides the IQNs of an iSCSI target, or the LUNs of an IQN, by probing the target from the pool coordinator.",
+
+		Attributes: map[string]schema.Attribute{
+			"target": schema.StringAttribute{
+				MarkdownDescription: "The IP address or hostname of the iSCSI target.",
+				Required:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The port of the iSCSI target, default to be `3260`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"target_iqn": schema.StringAttribute{
+				MarkdownDescription: "The IQN of the iSCSI target, the LUNs of the IQN are probed when it's set, otherwise the IQNs of the target are probed.",
+				Optional:            true,
+			},
+			"chap_username": schema.StringAttribute{
+				MarkdownDescription: "The CHAP username to authenticate with the iSCSI target.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("chap_password")),
+				},
+			},
+			"chap_password": schema.StringAttribute{
+				MarkdownDescription: "The CHAP password to authenticate with the iSCSI target.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("chap_username")),
+				},
+			},
+			"iqns": schema.ListAttribute{
+				MarkdownDescription: "The IQNs found on the iSCSI target.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"scsi_ids": schema.ListAttribute{
+				MarkdownDescription: "The SCSI IDs of the LUNs found on the IQN.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"data_items": schema.ListNestedAttribute{
+				MarkdownDescription: "The return items of the probe.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"configuration": schema.MapAttribute{
+							MarkdownDescription: "The device config which can be used to create the SR, for example, `targetIQN`, `SCSIid`.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"extra_info": schema.MapAttribute{
+							MarkdownDescription: "The additional information of the result, for example, the size, vendor and serial of the LUN.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"complete": schema.BoolAttribute{
+							MarkdownDescription: "True if the configuration is complete and can be used to create the SR.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *iscsiProbeDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.session = providerData.session
+}
+
+func (d *iscsiProbeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data iscsiProbeDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	port := int64(iscsiDefaultPort)
+	if !data.Port.IsNull() {
+		port = data.Port.ValueInt64()
+	}
+	deviceConfig := getISCSIDeviceConfig(data.Target.ValueString(), port, data.TargetIQN.ValueString(), "", data.ChapUsername.ValueString(), data.ChapPassword.ValueString())
+	results, err := probeISCSI(d.session, deviceConfig)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to probe iSCSI target",
+			err.Error(),
+		)
+		return
+	}
+	err = updateISCSIProbeDataSourceModel(ctx, results, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update iSCSI probe result data",
+			err.Error(),
+		)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,260 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource               = &isoResource{}
+	_ resource.ResourceWithConfigure  = &isoResource{}
+	_ resource.ResourceWithModifyPlan = &isoResource{}
+)
+
+func NewISOResource() resource.Resource {
+	return &isoResource{}
+}
+
+// isoResource defines the resource implementation.
+type isoResource struct {
+	session         *xenapi.Session
+	coordinatorConf *coordinatorConf
+}
+
+func (r *isoResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iso"
+}
+
+func (r *isoResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a resource to upload a local ISO file to an ISO library, for example, the NFS or SMB ISO library created by `xenserver_sr_nfs` or `xenserver_sr_smb` with `type = \"iso\"`, or a local ISO library. The ISO library is scanned after the upload, and `name_label` can be used as `cdrom` of `xenserver_vm`." +
+			"\n\n-> **Note:** The ISO library should be writable. The ISO file is removed from the ISO library when the resource is destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the ISO in the ISO library, default to be the file name of `source_file`, it should end with `.iso` or `.img`." +
+					"\n\n-> **Note:** The ISO is replaced when `name_label` is updated.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(isoNameRegex, "must end with .iso or .img"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sr_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the ISO library." +
+					"\n\n-> **Note:** The ISO is replaced when `sr_uuid` is updated.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_file": schema.StringAttribute{
+				MarkdownDescription: "The path of the local ISO file to upload." +
+					"\n\n-> **Note:** The ISO is replaced when `source_file` is updated.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_checksum": schema.StringAttribute{
+				MarkdownDescription: "The SHA-256 checksum of the local ISO file, for example, `filesha256(\"ubuntu.iso\")`, the checksum is verified before uploading." +
+					"\n\n-> **Note:** The ISO is replaced when `source_checksum` is updated, set it to replace the ISO when the ISO file changes.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"virtual_size": schema.Int64Attribute{
+				MarkdownDescription: "The size of the ISO (in bytes).",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the ISO virtual disk image.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the ISO virtual disk image.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *isoResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+	r.coordinatorConf = &providerData.coordinatorConf
+}
+
+func (r *isoResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data isoResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Uploading ISO...")
+	vdiRef, err := createISO(ctx, r.session, r.coordinatorConf, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to upload ISO",
+			err.Error(),
+		)
+		return
+	}
+	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI record",
+			err.Error(),
+		)
+		err = cleanupVDIResource(r.session, vdiRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up ISO resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	updateISOResourceModelComputed(vdiRecord, &data)
+	tflog.Debug(ctx, "ISO uploaded")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *isoResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data isoResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	vdiRef, err := xenapi.VDI.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI ref",
+			err.Error(),
+		)
+		return
+	}
+	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateISOResourceModel(r.session, vdiRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of ISOResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *isoResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan isoResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All the configurable attributes require replacement, nothing to update
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// ModifyPlan plans the ISO name from the file name of source_file when name_label is not set, and checks it
+func (r *isoResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// only check when creating the resource
+	if !req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan isoResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.SourceFile.IsUnknown() || !plan.NameLabel.IsUnknown() {
+		return
+	}
+	err := checkISOName(plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_file"),
+			"Invalid ISO name",
+			err.Error(),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name_label"), types.StringValue(getISOName(plan)))...)
+}
+
+func (r *isoResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data isoResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vdiRef, err := xenapi.VDI.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupVDIResource(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete ISO resource",
+			err.Error(),
+		)
+		return
+	}
+}
@@ -0,0 +1,69 @@
+package xenserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccISOResourceConfig(source_file string, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_sr_nfs" "nfs_iso" {
+	name_label       = "test NFS ISO library"
+	type             = "iso"
+	version          = "3"
+	storage_location = "%s"
+}
+
+resource "xenserver_iso" "test_iso" {
+	sr_uuid         = xenserver_sr_nfs.nfs_iso.uuid
+	source_file     = "%s"
+	source_checksum = filesha256("%s")
+	%s
+}
+`, os.Getenv("NFS_SERVER")+":"+os.Getenv("NFS_SERVER_PATH"), source_file, source_file, extra_config)
+}
+
+func TestAccISOResource(t *testing.T) {
+	sourceFile := filepath.Join(t.TempDir(), "test.iso")
+	err := os.WriteFile(sourceFile, make([]byte, 1024*1024), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalidSourceFile := filepath.Join(t.TempDir(), "test.txt")
+	err = os.WriteFile(invalidSourceFile, make([]byte, 1024), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccISOResourceConfig(invalidSourceFile, ""),
+				ExpectError: regexp.MustCompile(`should end with .iso or .img`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccISOResourceConfig(sourceFile, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_iso.test_iso", "name_label", "test.iso"),
+					resource.TestCheckResourceAttr("xenserver_iso.test_iso", "virtual_size", "1048576"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_iso.test_iso", "uuid"),
+				),
+			},
+			// Replace testing
+			{
+				Config: providerConfig + testAccISOResourceConfig(sourceFile, `name_label = "test-2.iso"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_iso.test_iso", "name_label", "test-2.iso"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
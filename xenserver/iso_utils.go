@@ -0,0 +1,130 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+type isoResourceModel struct {
+	NameLabel      types.String `tfsdk:"name_label"`
+	SR             types.String `tfsdk:"sr_uuid"`
+	SourceFile     types.String `tfsdk:"source_file"`
+	SourceChecksum types.String `tfsdk:"source_checksum"`
+	VirtualSize    types.Int64  `tfsdk:"virtual_size"`
+	UUID           types.String `tfsdk:"uuid"`
+	ID             types.String `tfsdk:"id"`
+}
+
+// isoNameRegex matches the ISO names which are recognized by the ISO library scan
+var isoNameRegex = regexp.MustCompile(`(?i)\.(iso|img)$`)
+
+// getISOName returns the name of the ISO in the ISO library, default to be the file name of the source file
+func getISOName(data isoResourceModel) string {
+	if !data.NameLabel.IsUnknown() && !data.NameLabel.IsNull() {
+		return data.NameLabel.ValueString()
+	}
+	return filepath.Base(data.SourceFile.ValueString())
+}
+
+// checkISOName checks the name of the ISO, the ISO library only recognizes the files ending with .iso or .img
+func checkISOName(data isoResourceModel) error {
+	name := getISOName(data)
+	if !isoNameRegex.MatchString(name) {
+		return fmt.Errorf(`the ISO name %q should end with .iso or .img, set "name_label" when the file name of "source_file" doesn't end with them`, name)
+	}
+	return nil
+}
+
+func getISOCreateParams(session *xenapi.Session, data isoResourceModel, size int64) (xenapi.VDIRecord, error) {
+	var record xenapi.VDIRecord
+	err := checkISOName(data)
+	if err != nil {
+		return record, err
+	}
+	srRef, err := xenapi.SR.GetByUUID(session, data.SR.ValueString())
+	if err != nil {
+		return record, errors.New(err.Error())
+	}
+	contentType, err := xenapi.SR.GetContentType(session, srRef)
+	if err != nil {
+		return record, errors.New(err.Error())
+	}
+	if contentType != "iso" {
+		return record, fmt.Errorf("the SR %s is not an ISO library, its content type is %s", data.SR.ValueString(), contentType)
+	}
+	record.NameLabel = getISOName(data)
+	record.SR = srRef
+	record.VirtualSize = int(size)
+	record.Type = xenapi.VdiTypeUser
+	return record, nil
+}
+
+// createISO creates the ISO in the ISO library, uploads the local ISO file and scans the ISO library
+func createISO(ctx context.Context, session *xenapi.Session, coordinatorConf *coordinatorConf, data isoResourceModel) (xenapi.VDIRef, error) {
+	var vdiRef xenapi.VDIRef
+	sourceFile, format, err := prepareVDIImportSource(ctx, vdiImportResourceModel{
+		SourceFile:     data.SourceFile,
+		SourceFormat:   types.StringValue(vdiImportFormatRaw),
+		SourceChecksum: data.SourceChecksum,
+	})
+	if err != nil {
+		return vdiRef, err
+	}
+	size, err := getVDIImportSize(sourceFile, format)
+	if err != nil {
+		return vdiRef, err
+	}
+	record, err := getISOCreateParams(session, data, size)
+	if err != nil {
+		return vdiRef, err
+	}
+	vdiRef, err = xenapi.VDI.Create(session, record)
+	if err != nil {
+		return vdiRef, errors.New(err.Error())
+	}
+	err = importRawVDI(ctx, session, coordinatorConf, vdiRef, record.SR, sourceFile, format)
+	if err == nil {
+		err = scanISOLibrary(session, record.SR)
+	}
+	if err != nil {
+		cleanupErr := cleanupVDIResource(session, vdiRef)
+		if cleanupErr != nil {
+			return vdiRef, errors.New(err.Error() + " " + cleanupErr.Error())
+		}
+		return vdiRef, err
+	}
+	return vdiRef, nil
+}
+
+func scanISOLibrary(session *xenapi.Session, srRef xenapi.SRRef) error {
+	err := xenapi.SR.Scan(session, srRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func updateISOResourceModel(session *xenapi.Session, record xenapi.VDIRecord, data *isoResourceModel) error {
+	srUUID, err := getUUIDFromSRRef(session, record.SR)
+	if err != nil {
+		return err
+	}
+	data.SR = types.StringValue(srUUID)
+	updateISOResourceModelComputed(record, data)
+
+	return nil
+}
+
+func updateISOResourceModelComputed(record xenapi.VDIRecord, data *isoResourceModel) {
+	data.NameLabel = types.StringValue(record.NameLabel)
+	data.VirtualSize = types.Int64Value(int64(record.VirtualSize))
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+}
@@ -47,6 +47,8 @@ func (d *networkDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 				MarkdownDescription: "The UUID of the network.",
 				Optional:            true,
 			},
+			"filter":       filterSchema(networkRecordData{}),
+			"expect_count": expectCountSchema(),
 			"data_items": schema.ListNestedAttribute{
 				MarkdownDescription: "The return items of networks.",
 				Computed:            true,
@@ -125,6 +127,14 @@ func (d *networkDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 							Computed:            true,
 							ElementType:         types.StringType,
 						},
+						"igmp_snooping_status": schema.StringAttribute{
+							MarkdownDescription: "The IGMP snooping status of the network, `\"enabled\"` or `\"disabled\"` if all the PIFs of the network have the same status, otherwise `\"unknown\"`.",
+							Computed:            true,
+						},
+						"vlan": schema.Int32Attribute{
+							MarkdownDescription: "The VLAN tag of the network, `-1` if it's not a VLAN network.",
+							Computed:            true,
+						},
 					},
 				},
 			},
@@ -185,6 +195,17 @@ func (d *networkDataSource) Read(ctx context.Context, req datasource.ReadRequest
 			)
 			return
 		}
+		matched, err := matchFilters(networkData, data.Filter)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to filter network record data",
+				err.Error(),
+			)
+			return
+		}
+		if !matched {
+			continue
+		}
 		networkItem = append(networkItem, networkData)
 	}
 
@@ -193,6 +214,14 @@ func (d *networkDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return networkItem[i].NameLabel.ValueString() < networkItem[j].NameLabel.ValueString()
 	})
 
+	err = checkExpectCount(data.ExpectCount, len(networkItem))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unexpected number of networks",
+			err.Error(),
+		)
+		return
+	}
 	data.DataItems = networkItem
 
 	// Save data into Terraform state
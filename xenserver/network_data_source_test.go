@@ -2,6 +2,7 @@ package xenserver
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -15,6 +16,26 @@ data "xenserver_network" "test_network_data" {
 `, name_label)
 }
 
+func testAccNetworkDataSourceFilterConfig(operator string, value string, expectCount int) string {
+	return fmt.Sprintf(`
+data "xenserver_network" "test_network_data" {
+	filter = [
+		{
+			name     = "name_label"
+			operator = "%s"
+			value    = "%s"
+		},
+		{
+			name     = "mtu"
+			operator = "ge"
+			value    = "1500"
+		}
+	]
+	expect_count = %d
+}
+`, operator, value, expectCount)
+}
+
 func TestAccNetworkDataSource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -26,6 +47,18 @@ func TestAccNetworkDataSource(t *testing.T) {
 					resource.TestCheckResourceAttrSet("data.xenserver_network.test_network_data", "data_items.#"),
 				),
 			},
+			{
+				Config:      providerConfig + testAccNetworkDataSourceFilterConfig("equals", "Pool-wide network associated with eth0", 0),
+				ExpectError: regexp.MustCompile(`expected 0 item\(s\), but found 1`),
+			},
+			{
+				Config: providerConfig + testAccNetworkDataSourceFilterConfig("regex", "^Pool-wide network associated with eth0$", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xenserver_network.test_network_data", "data_items.#", "1"),
+					resource.TestCheckResourceAttr("data.xenserver_network.test_network_data", "data_items.0.name_label", "Pool-wide network associated with eth0"),
+					resource.TestCheckResourceAttr("data.xenserver_network.test_network_data", "data_items.0.vlan", "-1"),
+				),
+			},
 		},
 	})
 }
@@ -0,0 +1,455 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &networkResource{}
+	_ resource.ResourceWithConfigure   = &networkResource{}
+	_ resource.ResourceWithImportState = &networkResource{}
+)
+
+func NewNetworkResource() resource.Resource {
+	return &networkResource{}
+}
+
+// networkResource defines the resource implementation.
+type networkResource struct {
+	session *xenapi.Session
+}
+
+func (r *networkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+func (r *networkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a network resource. Without `nic`, it is an internal network that passes traffic between VMs on the same host only. With `nic`, it is the external network that passes untagged traffic over the NIC or bond." +
+			"\n\n-> **Note:** The untagged traffic of a NIC always belongs to the network of its physical or bond PIFs, so with `nic` the existing network of the NIC is managed by the resource instead of creating a new one. Only the keys of `other_config` set by the resource are managed, and the original name, description, MTU, tags, `purpose` and `default_locking_mode` of the network are restored when the resource is destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the network.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the network, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"mtu": schema.Int32Attribute{
+				MarkdownDescription: "The MTU of the network, default to be `1500`. The minimum value this attribute can be set is `68`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1500),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(68),
+				},
+			},
+			"managed": schema.BoolAttribute{
+				MarkdownDescription: "True if the bridge is managed by [XAPI](https://github.com/xapi-project/xen-api), default to be `true`." +
+					"\n\n-> **Note:** `managed` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"other_config": schema.MapAttribute{
+				MarkdownDescription: "The additional configuration of the network, default to be `{}`. When `nic` is set, only the keys set in this attribute are managed.",
+				Optional:            true,
+				Computed:            true,
+				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				ElementType:         types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.NoneOf(networkTFOtherConfigKeysField, networkTFOriginalSettingsField)),
+				},
+			},
+			"tags": schema.SetAttribute{
+				MarkdownDescription: "The user-specified tags for categorization purposes, default to be `[]`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				ElementType:         types.StringType,
+			},
+			"purpose": schema.SetAttribute{
+				MarkdownDescription: "The set of purposes for which the server will use this network, default to be `[]`. Available values are `\"nbd\"` and `\"insecure_nbd\"`, they are not allowed to be set on the same network.",
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("nbd", "insecure_nbd")),
+					networkPurposeValidator{},
+				},
+			},
+			"default_locking_mode": schema.StringAttribute{
+				MarkdownDescription: "The network-wide default locking mode of the VIFs on the network, default to be `\"unlocked\"`. Available values are `\"unlocked\"` and `\"disabled\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("unlocked"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("unlocked", "disabled"),
+				},
+			},
+			"nic": schema.StringAttribute{
+				MarkdownDescription: "The NIC used by the external network, for example, `\"NIC 0\"`, `\"Bond 0+1\"`. The traffic of the network is untagged on the NIC, the existing network of the NIC is managed." + "<br />" +
+					"The NIC on target XenServer environment can be found by the `xenserver_nic` data-source. If not set, an internal network is created." +
+					"\n\n-> **Note:** `nic` is not allowed to be updated.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^NIC [0-9]+$|^Bond [0-9+]+$`),
+						`must be "NIC <index>" or "Bond <index>+<index>", eg. "NIC 0", "Bond 0+1"`,
+					),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *networkResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data networkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.NIC.IsNull() {
+		r.createNICNetwork(ctx, data, resp)
+		return
+	}
+
+	tflog.Debug(ctx, "Creating Network...")
+	networkRecord, err := getNetworkCreateParams(ctx, getNetworkBaseModel(data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network create params",
+			err.Error(),
+		)
+		return
+	}
+	networkRef, err := xenapi.Network.Create(r.session, networkRecord)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create network",
+			err.Error(),
+		)
+		return
+	}
+	err = updateNetworkSettings(ctx, r.session, networkRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update network settings",
+			err.Error(),
+		)
+		err = cleanupVlanResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	networkRecord, err = xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		err = cleanupVlanResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateNetworkResourceModelComputed(ctx, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of networkResourceModel",
+			err.Error(),
+		)
+		err = cleanupVlanResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+
+	tflog.Debug(ctx, "Network created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// createNICNetwork manages the existing network of the NIC which carries its untagged traffic
+func (r *networkResource) createNICNetwork(ctx context.Context, data networkResourceModel, resp *resource.CreateResponse) {
+	tflog.Debug(ctx, "Getting the network of "+data.NIC.ValueString()+"...")
+	networkRef, err := getUntaggedNetworkForNIC(r.session, data.NIC.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network of NIC",
+			err.Error(),
+		)
+		return
+	}
+	managed, err := xenapi.Network.GetManaged(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network managed",
+			err.Error(),
+		)
+		return
+	}
+	if managed != data.Managed.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Unable to manage network of NIC",
+			fmt.Sprintf("the network of %s has managed %t", data.NIC.ValueString(), managed),
+		)
+		return
+	}
+	err = saveNICNetworkSettings(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to save network settings",
+			err.Error(),
+		)
+		return
+	}
+	err = networkResourceModelUpdate(ctx, r.session, networkRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update network resource",
+			err.Error(),
+		)
+		r.cleanupNICNetwork(ctx, networkRef, resp)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		r.cleanupNICNetwork(ctx, networkRef, resp)
+		return
+	}
+	err = updateNetworkResourceModelComputed(ctx, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of networkResourceModel",
+			err.Error(),
+		)
+		r.cleanupNICNetwork(ctx, networkRef, resp)
+		return
+	}
+
+	tflog.Debug(ctx, "Network of "+data.NIC.ValueString()+" managed")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// cleanupNICNetwork restores the network of the NIC when it fails to be managed
+func (r *networkResource) cleanupNICNetwork(ctx context.Context, networkRef xenapi.NetworkRef, resp *resource.CreateResponse) {
+	err := restoreNICNetworkSettings(ctx, r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error cleaning up network resource",
+			err.Error(),
+		)
+	}
+}
+
+func (r *networkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data networkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateNetworkResourceModel(ctx, r.session, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of networkResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state networkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := networkResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_network configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	networkRef, err := xenapi.Network.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = networkResourceModelUpdate(ctx, r.session, networkRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update network resource",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateNetworkResourceModelComputed(ctx, networkRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of networkResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *networkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data networkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	// the network of the NIC is not created by the resource, keep it with the original settings
+	if !data.NIC.IsNull() {
+		tflog.Debug(ctx, "Restore the network of "+data.NIC.ValueString())
+		err = restoreNICNetworkSettings(ctx, r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to restore network settings",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = cleanupVlanResource(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete network resource",
+			err.Error(),
+		)
+		return
+	}
+}
+
+func (r *networkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
@@ -0,0 +1,126 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccNetworkResourceConfig(name_label string, name_description string, mtu int, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_network" "test_network" {
+	name_label = "%s"
+	name_description = "%s"
+	mtu = %d
+	%s
+}
+`, name_label, name_description, mtu, extra_config)
+}
+
+func TestAccNetworkResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccNetworkResourceConfig("test internal network 1", "", -1, ""),
+				ExpectError: regexp.MustCompile("Attribute mtu value must be at least 0"),
+			},
+			{
+				Config:      providerConfig + testAccNetworkResourceConfig("test internal network 1", "", 1500, `purpose = ["test"]`),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      providerConfig + testAccNetworkResourceConfig("test internal network 1", "", 1500, `purpose = ["nbd", "insecure_nbd"]`),
+				ExpectError: regexp.MustCompile(`"nbd" and "insecure_nbd" are not allowed to be set on the same network`),
+			},
+			{
+				Config:      providerConfig + testAccNetworkResourceConfig("test internal network 1", "", 1500, `nic = "NIC-SR-IOV 0"`),
+				ExpectError: regexp.MustCompile(`Attribute nic must be "NIC <index>" or "Bond <index>\+<index>"`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccNetworkResourceConfig("test internal network 1", "", 1500, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "name_label", "test internal network 1"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "other_config.%", "0"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "mtu", "1500"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "managed", "true"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "tags.#", "0"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "purpose.#", "0"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "default_locking_mode", "unlocked"),
+					resource.TestCheckNoResourceAttr("xenserver_network.test_network", "nic"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_network.test_network", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_network.test_network",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			{
+				Config:      providerConfig + testAccNetworkResourceConfig("test internal network 1", "", 1500, `nic = "NIC 0"`),
+				ExpectError: regexp.MustCompile(`"nic" doesn't expected to be updated`),
+			},
+			{
+				Config:      providerConfig + testAccNetworkResourceConfig("test internal network 1", "", 1500, "managed = false"),
+				ExpectError: regexp.MustCompile(`"managed" doesn't expected to be updated`),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccNetworkResourceConfig("test internal network 2", "Test description", 1600, `
+	tags = ["test"]
+	purpose = ["nbd"]
+	default_locking_mode = "disabled"
+	other_config = {
+		"flag" = "1"
+	}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "name_label", "test internal network 2"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "name_description", "Test description"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "other_config.%", "1"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "other_config.flag", "1"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "mtu", "1600"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "tags.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "tags.0", "test"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "purpose.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "purpose.0", "nbd"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "default_locking_mode", "disabled"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccNetworkResourceUntagged(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccNetworkResourceConfig("test untagged network", "", 1500, `nic = "NIC 1"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "name_label", "test untagged network"),
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "nic", "NIC 1"),
+					// the other config set by XAPI on the network of the NIC is not managed
+					resource.TestCheckResourceAttr("xenserver_network.test_network", "other_config.%", "0"),
+					resource.TestCheckResourceAttrSet("xenserver_network.test_network", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_network.test_network",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,330 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &sriovResource{}
+	_ resource.ResourceWithConfigure   = &sriovResource{}
+	_ resource.ResourceWithImportState = &sriovResource{}
+)
+
+func NewSriovResource() resource.Resource {
+	return &sriovResource{}
+}
+
+// sriovResource defines the resource implementation.
+type sriovResource struct {
+	session *xenapi.Session
+}
+
+func (r *sriovResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_sriov"
+}
+
+func (r *sriovResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides an SR-IOV network resource. SR-IOV is enabled on the physical NIC of every host, the network can be attached to the VIFs of the VMs for near line-rate networking performance.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the network.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the network, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"mtu": schema.Int32Attribute{
+				MarkdownDescription: "The MTU of the network, default to be `1500`. The minimum value this attribute can be set is `0`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1500),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+			},
+			"other_config": schema.MapAttribute{
+				MarkdownDescription: "The additional configuration of the network, default to be `{}`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				ElementType:         types.StringType,
+			},
+			"nic": schema.StringAttribute{
+				MarkdownDescription: "The physical NIC to enable SR-IOV on every host, for example, `\"NIC 1\"`." + "<br />" +
+					"The NIC on target XenServer environment which supports SR-IOV can be found by the `xenserver_nic` data-source with `network_type = \"sriov\"`." +
+					"\n\n-> **Note:** `nic` is not allowed to be updated.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^NIC [0-9]+$`),
+						`must be "NIC <index>", eg. "NIC 0"`,
+					),
+				},
+			},
+			"requires_reboot": schema.MapAttribute{
+				MarkdownDescription: "Whether the host requires a reboot to enable SR-IOV on the NIC, the key is the host UUID." +
+					"\n\n-> **Note:** The network can't be used on the host until the host is rebooted.",
+				Computed:    true,
+				ElementType: types.BoolType,
+			},
+			"remaining_capacity": schema.MapAttribute{
+				MarkdownDescription: "The number of the available virtual functions of the NIC on each host, the key is the host UUID.",
+				Computed:            true,
+				ElementType:         types.Int64Type,
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *sriovResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *sriovResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data sriovResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating Network...")
+	networkRecord, err := getNetworkCreateParams(ctx, getSriovNetworkBaseModel(data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network create params",
+			err.Error(),
+		)
+		return
+	}
+	networkRef, err := xenapi.Network.Create(r.session, networkRecord)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create network",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Enabling SR-IOV...")
+	err = createNetworkSriovs(r.session, networkRef, data.NIC.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create network_sriov",
+			err.Error(),
+		)
+		err = cleanupSriovResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	networkRecord, err = xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		err = cleanupSriovResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateSriovResourceModelComputed(ctx, r.session, networkRef, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of sriovResourceModel",
+			err.Error(),
+		)
+		err = cleanupSriovResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+
+	tflog.Debug(ctx, "SR-IOV network created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *sriovResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data sriovResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateSriovResourceModel(ctx, r.session, networkRef, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of sriovResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *sriovResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state sriovResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := sriovResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_network_sriov configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	networkRef, err := xenapi.Network.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = sriovResourceModelUpdate(ctx, r.session, networkRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update network_sriov resource",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateSriovResourceModelComputed(ctx, r.session, networkRef, networkRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of sriovResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *sriovResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data sriovResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupSriovResource(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete network resource",
+			err.Error(),
+		)
+		return
+	}
+}
+
+func (r *sriovResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
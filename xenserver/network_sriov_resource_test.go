@@ -0,0 +1,65 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccSriovResourceConfig(name_label string, name_description string, nic string) string {
+	return fmt.Sprintf(`
+data "xenserver_nic" "nic" {
+	network_type = "sriov"
+}
+
+resource "xenserver_network_sriov" "test_sriov" {
+	name_label = "%s"
+	name_description = "%s"
+	nic = %s
+}
+`, name_label, name_description, nic)
+}
+
+func TestAccSriovResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccSriovResourceConfig("test sriov network 1", "", `"NIC-SR-IOV 0"`),
+				ExpectError: regexp.MustCompile(`Attribute nic must be "NIC <index>"`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccSriovResourceConfig("test sriov network 1", "", "data.xenserver_nic.nic.data_items[0]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network_sriov.test_sriov", "name_label", "test sriov network 1"),
+					resource.TestCheckResourceAttr("xenserver_network_sriov.test_sriov", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_network_sriov.test_sriov", "mtu", "1500"),
+					resource.TestCheckResourceAttrPair("xenserver_network_sriov.test_sriov", "nic", "data.xenserver_nic.nic", "data_items.0"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_network_sriov.test_sriov", "requires_reboot.%"),
+					resource.TestCheckResourceAttrSet("xenserver_network_sriov.test_sriov", "remaining_capacity.%"),
+					resource.TestCheckResourceAttrSet("xenserver_network_sriov.test_sriov", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_network_sriov.test_sriov",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccSriovResourceConfig("test sriov network 2", "Test description", "data.xenserver_nic.nic.data_items[0]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network_sriov.test_sriov", "name_label", "test sriov network 2"),
+					resource.TestCheckResourceAttr("xenserver_network_sriov.test_sriov", "name_description", "Test description"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
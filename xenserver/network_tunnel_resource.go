@@ -0,0 +1,355 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &tunnelResource{}
+	_ resource.ResourceWithConfigure   = &tunnelResource{}
+	_ resource.ResourceWithImportState = &tunnelResource{}
+	_ resource.ResourceWithModifyPlan  = &tunnelResource{}
+)
+
+func NewTunnelResource() resource.Resource {
+	return &tunnelResource{}
+}
+
+// tunnelResource defines the resource implementation.
+type tunnelResource struct {
+	session *xenapi.Session
+}
+
+func (r *tunnelResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_tunnel"
+}
+
+func (r *tunnelResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a cross-server private network resource. A network that passes traffic between VMs on different hosts of the pool over GRE or VXLAN tunnels, a tunnel is created on the transport PIF of every host.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the network.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the network, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"mtu": schema.Int32Attribute{
+				MarkdownDescription: "The MTU of the network, default to be `1500`. The minimum value this attribute can be set is `0`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1500),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+			},
+			"other_config": schema.MapAttribute{
+				MarkdownDescription: "The additional configuration of the network, default to be `{}`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				ElementType:         types.StringType,
+			},
+			"protocol": schema.StringAttribute{
+				MarkdownDescription: "The protocol used by the tunnels, default to be `\"gre\"`. Available values are `\"gre\"` and `\"vxlan\"`." +
+					"\n\n-> **Note:** `protocol` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("gre"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("gre", "vxlan"),
+				},
+			},
+			"nic": schema.StringAttribute{
+				MarkdownDescription: "The NIC used as the transport PIF of the tunnels on every host, for example, `\"NIC 1\"`, `\"Bond 0+1\"`. The NIC should have an IP address configured on every host, default to use the management interface of every host." + "<br />" +
+					"The NIC on target XenServer environment can be found by the `xenserver_nic` data-source." +
+					"\n\n-> **Note:** `nic` is not allowed to be updated.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^NIC [0-9]+$|^Bond [0-9+]+$`),
+						`must be "NIC <index>" or "Bond <index>+<index>", eg. "NIC 0", "Bond 0+1"`,
+					),
+				},
+			},
+			"status": schema.MapAttribute{
+				MarkdownDescription: "The status of the tunnel on each host, the key is the host UUID and the value is `\"true\"` if the tunnel is active." + "<br />" +
+					"When a host joins the pool, the tunnel on it will be created in the next apply.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *tunnelResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *tunnelResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data tunnelResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating Network...")
+	networkRecord, err := getNetworkCreateParams(ctx, getTunnelNetworkBaseModel(data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network create params",
+			err.Error(),
+		)
+		return
+	}
+	networkRef, err := xenapi.Network.Create(r.session, networkRecord)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create network",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Creating Tunnels...")
+	err = createTunnels(r.session, networkRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create tunnels",
+			err.Error(),
+		)
+		err = cleanupTunnelResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	networkRecord, err = xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		err = cleanupTunnelResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateTunnelResourceModelComputed(ctx, r.session, networkRef, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of tunnelResourceModel",
+			err.Error(),
+		)
+		err = cleanupTunnelResource(r.session, networkRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up network resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+
+	tflog.Debug(ctx, "Cross-server private network created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *tunnelResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data tunnelResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateTunnelResourceModel(ctx, r.session, networkRef, networkRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of tunnelResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *tunnelResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state tunnelResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := tunnelResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_network_tunnel configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	networkRef, err := xenapi.Network.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = tunnelResourceModelUpdate(ctx, r.session, networkRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update network_tunnel resource",
+			err.Error(),
+		)
+		return
+	}
+	networkRecord, err := xenapi.Network.GetRecord(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateTunnelResourceModelComputed(ctx, r.session, networkRef, networkRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of tunnelResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// ModifyPlan plans an update to create the tunnels when hosts are added to the pool
+func (r *tunnelResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.session == nil {
+		return
+	}
+	var state tunnelResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changed, err := isTunnelHostsChanged(ctx, r.session, state.Status)
+	if err != nil || !changed {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.MapUnknown(types.StringType))...)
+}
+
+func (r *tunnelResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data tunnelResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	networkRef, err := xenapi.Network.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get network ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupTunnelResource(r.session, networkRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete network resource",
+			err.Error(),
+		)
+		return
+	}
+}
+
+func (r *tunnelResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
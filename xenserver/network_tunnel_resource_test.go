@@ -0,0 +1,72 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccTunnelResourceConfig(name_label string, name_description string, mtu int, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_network_tunnel" "test_tunnel" {
+	name_label = "%s"
+	name_description = "%s"
+	mtu = %d
+	%s
+}
+`, name_label, name_description, mtu, extra_config)
+}
+
+func TestAccTunnelResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccTunnelResourceConfig("test tunnel network 1", "", 1500, `protocol = "ipip"`),
+				ExpectError: regexp.MustCompile(`Attribute protocol value must be one of`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccTunnelResourceConfig("test tunnel network 1", "", 1500, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "name_label", "test tunnel network 1"),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "other_config.%", "0"),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "mtu", "1500"),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "protocol", "gre"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_network_tunnel.test_tunnel", "status.%"),
+					resource.TestCheckResourceAttrSet("xenserver_network_tunnel.test_tunnel", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_network_tunnel.test_tunnel",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			{
+				Config:      providerConfig + testAccTunnelResourceConfig("test tunnel network 1", "", 1500, `protocol = "vxlan"`),
+				ExpectError: regexp.MustCompile(`"protocol" doesn't expected to be updated`),
+			},
+			{
+				Config:      providerConfig + testAccTunnelResourceConfig("test tunnel network 1", "", 1500, `nic = "NIC 0"`),
+				ExpectError: regexp.MustCompile(`"nic" doesn't expected to be updated`),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccTunnelResourceConfig("test tunnel network 2", "Test description", 1450, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "name_label", "test tunnel network 2"),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "name_description", "Test description"),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "mtu", "1450"),
+					resource.TestCheckResourceAttr("xenserver_network_tunnel.test_tunnel", "protocol", "gre"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -2,21 +2,26 @@ package xenserver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"xenapi"
 )
 
 type networkDataSourceModel struct {
-	NameLabel types.String        `tfsdk:"name_label"`
-	UUID      types.String        `tfsdk:"uuid"`
-	DataItems []networkRecordData `tfsdk:"data_items"`
+	NameLabel   types.String        `tfsdk:"name_label"`
+	UUID        types.String        `tfsdk:"uuid"`
+	Filter      []filterObject      `tfsdk:"filter"`
+	ExpectCount types.Int64         `tfsdk:"expect_count"`
+	DataItems   []networkRecordData `tfsdk:"data_items"`
 }
 
 type networkRecordData struct {
@@ -36,6 +41,39 @@ type networkRecordData struct {
 	DefaultLockingMode types.String `tfsdk:"default_locking_mode"`
 	AssignedIps        types.Map    `tfsdk:"assigned_ips"`
 	Purpose            types.List   `tfsdk:"purpose"`
+	IGMPSnoopingStatus types.String `tfsdk:"igmp_snooping_status"`
+	VLAN               types.Int32  `tfsdk:"vlan"`
+}
+
+// getNetworkIGMPSnoopingStatus returns the IGMP snooping status shared by all the PIFs of the network
+func getNetworkIGMPSnoopingStatus(session *xenapi.Session, pifRefs []xenapi.PIFRef) (string, error) {
+	status := "unknown"
+	for i, pifRef := range pifRefs {
+		pifStatus, err := xenapi.PIF.GetIgmpSnoopingStatus(session, pifRef)
+		if err != nil {
+			return status, errors.New(err.Error())
+		}
+		if i == 0 {
+			status = string(pifStatus)
+		} else if status != string(pifStatus) {
+			return "unknown", nil
+		}
+	}
+	return status, nil
+}
+
+// getNetworkVLAN returns the VLAN tag of the PIFs of the network, -1 if the network is not a VLAN network
+func getNetworkVLAN(session *xenapi.Session, pifRefs []xenapi.PIFRef) (int32, error) {
+	for _, pifRef := range pifRefs {
+		vlan, err := xenapi.PIF.GetVLAN(session, pifRef)
+		if err != nil {
+			return -1, errors.New(err.Error())
+		}
+		if vlan >= 0 {
+			return ToInt32(vlan)
+		}
+	}
+	return -1, nil
 }
 
 func updateNetworkRecordData(ctx context.Context, session *xenapi.Session, record xenapi.NetworkRecord, data *networkRecordData) error {
@@ -104,6 +142,16 @@ func updateNetworkRecordData(ctx context.Context, session *xenapi.Session, recor
 	if diags.HasError() {
 		return errors.New("unable to read network purpose")
 	}
+	igmpSnoopingStatus, err := getNetworkIGMPSnoopingStatus(session, record.PIFs)
+	if err != nil {
+		return err
+	}
+	data.IGMPSnoopingStatus = types.StringValue(igmpSnoopingStatus)
+	vlan, err := getNetworkVLAN(session, record.PIFs)
+	if err != nil {
+		return err
+	}
+	data.VLAN = types.Int32Value(vlan)
 
 	return nil
 }
@@ -116,17 +164,39 @@ type vlanResourceModel struct {
 	OtherConfig     types.Map    `tfsdk:"other_config"`
 	Tag             types.Int32  `tfsdk:"vlan_tag"`
 	NIC             types.String `tfsdk:"nic"`
+	Hosts           types.Set    `tfsdk:"hosts"`
+	PIFs            types.Map    `tfsdk:"pifs"`
 	UUID            types.String `tfsdk:"uuid"`
 	ID              types.String `tfsdk:"id"`
 }
 
+type vlanPIFModel struct {
+	UUID              types.String `tfsdk:"uuid"`
+	CurrentlyAttached types.Bool   `tfsdk:"currently_attached"`
+}
+
+var vlanPIFModelAttrTypes = map[string]attr.Type{
+	"uuid":               types.StringType,
+	"currently_attached": types.BoolType,
+}
+
 type vlanCreateParams struct {
 	PifRef     xenapi.PIFRef
 	NetworkRef xenapi.NetworkRef
 	Tag        int
 }
 
-func getNetworkCreateParams(ctx context.Context, data vlanResourceModel) (xenapi.NetworkRecord, error) {
+// networkBaseModel holds the fields shared by the network resources when creating the network
+type networkBaseModel struct {
+	NameLabel       types.String
+	NameDescription types.String
+	MTU             types.Int32
+	Managed         types.Bool
+	OtherConfig     types.Map
+	Tags            types.Set
+}
+
+func getNetworkCreateParams(ctx context.Context, data networkBaseModel) (xenapi.NetworkRecord, error) {
 	var record xenapi.NetworkRecord
 	record.NameLabel = data.NameLabel.ValueString()
 	record.NameDescription = data.NameDescription.ValueString()
@@ -134,7 +204,14 @@ func getNetworkCreateParams(ctx context.Context, data vlanResourceModel) (xenapi
 	record.Managed = data.Managed.ValueBool()
 	diags := data.OtherConfig.ElementsAs(ctx, &record.OtherConfig, false)
 	if diags.HasError() {
-		return record, errors.New("unable to access vlan other config")
+		return record, errors.New("unable to access network other config")
+	}
+	record.Tags = []string{}
+	if !data.Tags.IsNull() && !data.Tags.IsUnknown() {
+		diags = data.Tags.ElementsAs(ctx, &record.Tags, false)
+		if diags.HasError() {
+			return record, errors.New("unable to access network tags")
+		}
 	}
 
 	return record, nil
@@ -218,6 +295,123 @@ func getVlanCreateParams(session *xenapi.Session, data vlanResourceModel, networ
 	return params, nil
 }
 
+// getVlanTaggedPIFs returns the PIF of the NIC on each host which should have the VLAN, keyed by the host UUID.
+// All the hosts which have the NIC are returned when hosts is null.
+func getVlanTaggedPIFs(ctx context.Context, session *xenapi.Session, nic string, hosts types.Set) (map[string]xenapi.PIFRef, error) {
+	taggedPIFs := make(map[string]xenapi.PIFRef)
+	pifRefs, err := getPifRefsForNIC(session, nic)
+	if err != nil {
+		return taggedPIFs, err
+	}
+	for _, pifRef := range pifRefs {
+		hostRef, err := xenapi.PIF.GetHost(session, pifRef)
+		if err != nil {
+			return taggedPIFs, errors.New(err.Error())
+		}
+		hostUUID, err := xenapi.Host.GetUUID(session, hostRef)
+		if err != nil {
+			return taggedPIFs, errors.New(err.Error())
+		}
+		taggedPIFs[hostUUID] = pifRef
+	}
+	if hosts.IsNull() {
+		return taggedPIFs, nil
+	}
+
+	var hostUUIDs []string
+	diags := hosts.ElementsAs(ctx, &hostUUIDs, false)
+	if diags.HasError() {
+		return taggedPIFs, errors.New("unable to access network_vlan hosts")
+	}
+	selectedPIFs := make(map[string]xenapi.PIFRef)
+	for _, hostUUID := range hostUUIDs {
+		pifRef, ok := taggedPIFs[hostUUID]
+		if !ok {
+			return selectedPIFs, fmt.Errorf("unable to find %s on host %s", nic, hostUUID)
+		}
+		selectedPIFs[hostUUID] = pifRef
+	}
+	return selectedPIFs, nil
+}
+
+// getVlanPIFsByHost returns the VLAN PIFs of the network keyed by the host UUID
+func getVlanPIFsByHost(session *xenapi.Session, networkRef xenapi.NetworkRef) (map[string]xenapi.PIFRecord, error) {
+	vlanPIFs := make(map[string]xenapi.PIFRecord)
+	pifRefs, err := xenapi.Network.GetPIFs(session, networkRef)
+	if err != nil {
+		return vlanPIFs, errors.New(err.Error())
+	}
+	for _, pifRef := range pifRefs {
+		pifRecord, err := xenapi.PIF.GetRecord(session, pifRef)
+		if err != nil {
+			return vlanPIFs, errors.New(err.Error())
+		}
+		hostUUID, err := xenapi.Host.GetUUID(session, pifRecord.Host)
+		if err != nil {
+			return vlanPIFs, errors.New(err.Error())
+		}
+		vlanPIFs[hostUUID] = pifRecord
+	}
+	return vlanPIFs, nil
+}
+
+// updateVlanPIFs creates the VLAN on the hosts which should have it but don't have it yet,
+// and removes the VLAN from the hosts which are not in hosts any more.
+func updateVlanPIFs(ctx context.Context, session *xenapi.Session, networkRef xenapi.NetworkRef, data vlanResourceModel) error {
+	taggedPIFs, err := getVlanTaggedPIFs(ctx, session, data.NIC.ValueString(), data.Hosts)
+	if err != nil {
+		return err
+	}
+	vlanPIFs, err := getVlanPIFsByHost(session, networkRef)
+	if err != nil {
+		return err
+	}
+	for hostUUID, pifRef := range taggedPIFs {
+		if _, ok := vlanPIFs[hostUUID]; ok {
+			continue
+		}
+		_, err = xenapi.VLAN.Create(session, pifRef, int(data.Tag.ValueInt32()), networkRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	if data.Hosts.IsNull() {
+		return nil
+	}
+	for hostUUID, pifRecord := range vlanPIFs {
+		if _, ok := taggedPIFs[hostUUID]; ok {
+			continue
+		}
+		err = xenapi.VLAN.Destroy(session, pifRecord.VLANMasterOf)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// isVlanHostsChanged returns true if the hosts which should have the VLAN are different from the hosts in pifs
+func isVlanHostsChanged(ctx context.Context, session *xenapi.Session, data vlanResourceModel, pifs types.Map) (bool, error) {
+	taggedPIFs, err := getVlanTaggedPIFs(ctx, session, data.NIC.ValueString(), data.Hosts)
+	if err != nil {
+		return false, err
+	}
+	pifsMap := make(map[string]vlanPIFModel)
+	diags := pifs.ElementsAs(ctx, &pifsMap, false)
+	if diags.HasError() {
+		return false, errors.New("unable to access network_vlan pifs")
+	}
+	if len(taggedPIFs) != len(pifsMap) {
+		return true, nil
+	}
+	for hostUUID := range taggedPIFs {
+		if _, ok := pifsMap[hostUUID]; !ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func getNICFromPIF(session *xenapi.Session, pifRecord xenapi.PIFRecord) (string, error) {
 	// return eg. NIC 0, NIC-SR-IOV 0, Bond 0+1+2
 	name := ""
@@ -278,10 +472,10 @@ func updateVlanResourceModel(ctx context.Context, session *xenapi.Session, recor
 	}
 	data.NIC = types.StringValue(nicName)
 
-	return updateVlanResourceModelComputed(ctx, record, data)
+	return updateVlanResourceModelComputed(ctx, session, record, data)
 }
 
-func updateVlanResourceModelComputed(ctx context.Context, record xenapi.NetworkRecord, data *vlanResourceModel) error {
+func updateVlanResourceModelComputed(ctx context.Context, session *xenapi.Session, record xenapi.NetworkRecord, data *vlanResourceModel) error {
 	data.UUID = types.StringValue(record.UUID)
 	data.ID = types.StringValue(record.UUID)
 	data.NameDescription = types.StringValue(record.NameDescription)
@@ -296,6 +490,25 @@ func updateVlanResourceModelComputed(ctx context.Context, record xenapi.NetworkR
 	if diags.HasError() {
 		return errors.New("unable to update data for network_vlan other_config")
 	}
+	networkRef, err := xenapi.Network.GetByUUID(session, record.UUID)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	vlanPIFs, err := getVlanPIFsByHost(session, networkRef)
+	if err != nil {
+		return err
+	}
+	pifs := make(map[string]vlanPIFModel)
+	for hostUUID, pifRecord := range vlanPIFs {
+		pifs[hostUUID] = vlanPIFModel{
+			UUID:              types.StringValue(pifRecord.UUID),
+			CurrentlyAttached: types.BoolValue(pifRecord.CurrentlyAttached),
+		}
+	}
+	data.PIFs, diags = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: vlanPIFModelAttrTypes}, pifs)
+	if diags.HasError() {
+		return errors.New("unable to update data for network_vlan pifs")
+	}
 
 	return nil
 }
@@ -336,7 +549,8 @@ func vlanResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref x
 	if err != nil {
 		return errors.New(err.Error())
 	}
-	return nil
+	// hosts may have been changed or joined the pool since the VLAN was created
+	return updateVlanPIFs(ctx, session, ref, data)
 }
 
 func cleanupVlanResource(session *xenapi.Session, ref xenapi.NetworkRef) error {
@@ -361,6 +575,353 @@ func cleanupVlanResource(session *xenapi.Session, ref xenapi.NetworkRef) error {
 	return nil
 }
 
+type networkResourceModel struct {
+	NameLabel          types.String `tfsdk:"name_label"`
+	NameDescription    types.String `tfsdk:"name_description"`
+	MTU                types.Int32  `tfsdk:"mtu"`
+	Managed            types.Bool   `tfsdk:"managed"`
+	OtherConfig        types.Map    `tfsdk:"other_config"`
+	Tags               types.Set    `tfsdk:"tags"`
+	Purpose            types.Set    `tfsdk:"purpose"`
+	DefaultLockingMode types.String `tfsdk:"default_locking_mode"`
+	NIC                types.String `tfsdk:"nic"`
+	UUID               types.String `tfsdk:"uuid"`
+	ID                 types.String `tfsdk:"id"`
+}
+
+func getNetworkBaseModel(data networkResourceModel) networkBaseModel {
+	return networkBaseModel{
+		NameLabel:       data.NameLabel,
+		NameDescription: data.NameDescription,
+		MTU:             data.MTU,
+		Managed:         data.Managed,
+		OtherConfig:     data.OtherConfig,
+		Tags:            data.Tags,
+	}
+}
+
+// getUntaggedNetworkForNIC returns the network of the physical or bond PIFs of the NIC, which carries the untagged traffic on the NIC
+func getUntaggedNetworkForNIC(session *xenapi.Session, nic string) (xenapi.NetworkRef, error) {
+	var networkRef xenapi.NetworkRef
+	pifRefs, err := getPifRefsForNIC(session, nic)
+	if err != nil {
+		return networkRef, err
+	}
+	if len(pifRefs) == 0 {
+		return networkRef, errors.New("unable to find PIF for NIC " + nic)
+	}
+	for _, pifRef := range pifRefs {
+		pifNetworkRef, err := xenapi.PIF.GetNetwork(session, pifRef)
+		if err != nil {
+			return networkRef, errors.New(err.Error())
+		}
+		if networkRef != "" && pifNetworkRef != networkRef {
+			return networkRef, errors.New("the PIFs of NIC " + nic + " are attached to different networks on the hosts")
+		}
+		networkRef = pifNetworkRef
+	}
+	return networkRef, nil
+}
+
+// networkPurposeValidator checks "nbd" and "insecure_nbd" are not set on the same network
+type networkPurposeValidator struct{}
+
+func (v networkPurposeValidator) Description(_ context.Context) string {
+	return `"nbd" and "insecure_nbd" are not allowed to be set on the same network`
+}
+
+func (v networkPurposeValidator) MarkdownDescription(_ context.Context) string {
+	return "`\"nbd\"` and `\"insecure_nbd\"` are not allowed to be set on the same network"
+}
+
+func (v networkPurposeValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	purposes := []string{}
+	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &purposes, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if slices.Contains(purposes, "nbd") && slices.Contains(purposes, "insecure_nbd") {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid network purpose",
+			v.Description(ctx),
+		)
+	}
+}
+
+func updateNetworkPurpose(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, data networkResourceModel) error {
+	purposes := []string{}
+	diags := data.Purpose.ElementsAs(ctx, &purposes, false)
+	if diags.HasError() {
+		return errors.New("unable to access network purpose")
+	}
+	current, err := xenapi.Network.GetPurpose(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	for _, purpose := range current {
+		if slices.Contains(purposes, string(purpose)) {
+			continue
+		}
+		err = xenapi.Network.RemovePurpose(session, ref, purpose)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	for _, purpose := range purposes {
+		if slices.Contains(current, xenapi.NetworkPurpose(purpose)) {
+			continue
+		}
+		err = xenapi.Network.AddPurpose(session, ref, xenapi.NetworkPurpose(purpose))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+func updateNetworkSettings(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, data networkResourceModel) error {
+	err := xenapi.Network.SetDefaultLockingMode(session, ref, xenapi.NetworkDefaultLockingMode(data.DefaultLockingMode.ValueString()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return updateNetworkPurpose(ctx, session, ref, data)
+}
+
+func updateNetworkResourceModel(ctx context.Context, session *xenapi.Session, record xenapi.NetworkRecord, data *networkResourceModel) error {
+	data.NameLabel = types.StringValue(record.NameLabel)
+	data.NIC = types.StringNull()
+	if len(record.PIFs) > 0 {
+		pifRecord, err := xenapi.PIF.GetRecord(session, record.PIFs[0])
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		nicName, err := getNICFromPIF(session, pifRecord)
+		if err != nil {
+			return err
+		}
+		data.NIC = types.StringValue(nicName)
+	}
+
+	return updateNetworkResourceModelComputed(ctx, record, data)
+}
+
+func updateNetworkResourceModelComputed(ctx context.Context, record xenapi.NetworkRecord, data *networkResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	mtu, err := ToInt32(record.MTU)
+	if err != nil {
+		return err
+	}
+	data.MTU = types.Int32Value(mtu)
+	data.Managed = types.BoolValue(record.Managed)
+	var diags diag.Diagnostics
+	if !data.NIC.IsNull() {
+		// only the keys set by the resource are read from the network of the NIC
+		data.OtherConfig, err = getTFManagedMap(ctx, record.OtherConfig, record.OtherConfig[networkTFOtherConfigKeysField])
+		if err != nil {
+			return err
+		}
+	} else {
+		data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
+		if diags.HasError() {
+			return errors.New("unable to update data for network other_config")
+		}
+	}
+	data.Tags, diags = types.SetValueFrom(ctx, types.StringType, record.Tags)
+	if diags.HasError() {
+		return errors.New("unable to update data for network tags")
+	}
+	data.Purpose, diags = types.SetValueFrom(ctx, types.StringType, record.Purpose)
+	if diags.HasError() {
+		return errors.New("unable to update data for network purpose")
+	}
+	data.DefaultLockingMode = types.StringValue(string(record.DefaultLockingMode))
+
+	return nil
+}
+
+func networkResourceModelUpdateCheck(data networkResourceModel, dataState networkResourceModel) error {
+	if data.NIC != dataState.NIC {
+		return errors.New(`"nic" doesn't expected to be updated`)
+	}
+	if data.Managed != dataState.Managed {
+		return errors.New(`"managed" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+func networkResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, data networkResourceModel) error {
+	err := xenapi.Network.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetMTU(session, ref, int(data.MTU.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	otherConfig, err := getNetworkOtherConfig(ctx, session, ref, data)
+	if err != nil {
+		return err
+	}
+	err = xenapi.Network.SetOtherConfig(session, ref, otherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	tags := []string{}
+	diags := data.Tags.ElementsAs(ctx, &tags, false)
+	if diags.HasError() {
+		return errors.New("unable to access network tags")
+	}
+	err = xenapi.Network.SetTags(session, ref, tags)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return updateNetworkSettings(ctx, session, ref, data)
+}
+
+const (
+	networkTFOtherConfigKeysField = "tf_other_config_keys"
+	// networkTFOriginalSettingsField records the settings of the network of the NIC before it's managed by the resource
+	networkTFOriginalSettingsField = "tf_original_settings"
+)
+
+// nicNetworkSettings are the settings of the network of the NIC which are restored when the resource is destroyed
+type nicNetworkSettings struct {
+	NameLabel          string   `json:"nameLabel"`
+	NameDescription    string   `json:"nameDescription"`
+	MTU                int      `json:"mtu"`
+	Tags               []string `json:"tags"`
+	Purpose            []string `json:"purpose"`
+	DefaultLockingMode string   `json:"defaultLockingMode"`
+}
+
+// getNetworkOtherConfig returns the other config to set on the network. The other config of the network created by
+// the resource is replaced, while only the keys set by the resource are changed on the network of the NIC.
+func getNetworkOtherConfig(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, data networkResourceModel) (map[string]string, error) {
+	otherConfig := make(map[string]string)
+	if data.NIC.IsNull() {
+		diags := data.OtherConfig.ElementsAs(ctx, &otherConfig, false)
+		if diags.HasError() {
+			return otherConfig, errors.New("unable to access network other config")
+		}
+		return otherConfig, nil
+	}
+	current, err := xenapi.Network.GetOtherConfig(session, ref)
+	if err != nil {
+		return otherConfig, errors.New(err.Error())
+	}
+	otherConfig, keys, err := mergeTFManagedMap(ctx, current, current[networkTFOtherConfigKeysField], data.OtherConfig)
+	if err != nil {
+		return otherConfig, err
+	}
+	delete(otherConfig, networkTFOtherConfigKeysField)
+	if keys != "" {
+		otherConfig[networkTFOtherConfigKeysField] = keys
+	}
+	return otherConfig, nil
+}
+
+// saveNICNetworkSettings records the original settings of the network of the NIC in its other config,
+// the settings recorded by an earlier resource are kept
+func saveNICNetworkSettings(session *xenapi.Session, ref xenapi.NetworkRef) error {
+	record, err := xenapi.Network.GetRecord(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if _, ok := record.OtherConfig[networkTFOriginalSettingsField]; ok {
+		return nil
+	}
+	settings := nicNetworkSettings{
+		NameLabel:          record.NameLabel,
+		NameDescription:    record.NameDescription,
+		MTU:                record.MTU,
+		Tags:               record.Tags,
+		DefaultLockingMode: string(record.DefaultLockingMode),
+	}
+	for _, purpose := range record.Purpose {
+		settings.Purpose = append(settings.Purpose, string(purpose))
+	}
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	record.OtherConfig[networkTFOriginalSettingsField] = string(value)
+	err = xenapi.Network.SetOtherConfig(session, ref, record.OtherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// restoreNICNetworkSettings restores the original settings of the network of the NIC, and removes the keys set by the resource
+func restoreNICNetworkSettings(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef) error {
+	otherConfig, err := xenapi.Network.GetOtherConfig(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	value, ok := otherConfig[networkTFOriginalSettingsField]
+	if ok {
+		var settings nicNetworkSettings
+		err = json.Unmarshal([]byte(value), &settings)
+		if err != nil {
+			return errors.New("unable to read the original settings of the network. " + err.Error())
+		}
+		purpose, diags := types.SetValueFrom(ctx, types.StringType, settings.Purpose)
+		if diags.HasError() {
+			return errors.New("unable to access network purpose")
+		}
+		err = restoreNetworkSettings(ctx, session, ref, settings, purpose)
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range strings.Split(otherConfig[networkTFOtherConfigKeysField], ",") {
+		delete(otherConfig, key)
+	}
+	delete(otherConfig, networkTFOtherConfigKeysField)
+	delete(otherConfig, networkTFOriginalSettingsField)
+	err = xenapi.Network.SetOtherConfig(session, ref, otherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func restoreNetworkSettings(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, settings nicNetworkSettings, purpose types.Set) error {
+	err := xenapi.Network.SetNameLabel(session, ref, settings.NameLabel)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetNameDescription(session, ref, settings.NameDescription)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetMTU(session, ref, settings.MTU)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if settings.Tags == nil {
+		settings.Tags = []string{}
+	}
+	err = xenapi.Network.SetTags(session, ref, settings.Tags)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return updateNetworkSettings(ctx, session, ref, networkResourceModel{
+		DefaultLockingMode: types.StringValue(settings.DefaultLockingMode),
+		Purpose:            purpose,
+	})
+}
+
 type nicDataSourceModel struct {
 	NetworkType types.String `tfsdk:"network_type"`
 	DataItems   []string     `tfsdk:"data_items"`
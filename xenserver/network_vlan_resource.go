@@ -6,6 +6,7 @@ import (
 	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -29,6 +30,7 @@ var (
 	_ resource.Resource                = &vlanResource{}
 	_ resource.ResourceWithConfigure   = &vlanResource{}
 	_ resource.ResourceWithImportState = &vlanResource{}
+	_ resource.ResourceWithModifyPlan  = &vlanResource{}
 )
 
 func NewVlanResource() resource.Resource {
@@ -98,6 +100,31 @@ func (r *vlanResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					),
 				},
 			},
+			"hosts": schema.SetAttribute{
+				MarkdownDescription: "The UUIDs of the hosts to create the VLAN on, the VLAN is created on all the hosts which have the NIC when it's not set." + "<br />" +
+					"When a host which should have the VLAN joins the pool, the VLAN on it will be created in the next apply.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"pifs": schema.MapNestedAttribute{
+				MarkdownDescription: "The VLAN PIF on each host, the key is the host UUID.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the VLAN PIF.",
+							Computed:            true,
+						},
+						"currently_attached": schema.BoolAttribute{
+							MarkdownDescription: "True if the VLAN PIF is online.",
+							Computed:            true,
+						},
+					},
+				},
+			},
 			"uuid": schema.StringAttribute{
 				MarkdownDescription: "The UUID of the network.",
 				Computed:            true,
@@ -140,7 +167,14 @@ func (r *vlanResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 
 	tflog.Debug(ctx, "Creating Network...")
-	networkRecord, err := getNetworkCreateParams(ctx, data)
+	networkRecord, err := getNetworkCreateParams(ctx, networkBaseModel{
+		NameLabel:       data.NameLabel,
+		NameDescription: data.NameDescription,
+		MTU:             data.MTU,
+		Managed:         data.Managed,
+		OtherConfig:     data.OtherConfig,
+		Tags:            types.SetNull(types.StringType),
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get network create params",
@@ -156,25 +190,32 @@ func (r *vlanResource) Create(ctx context.Context, req resource.CreateRequest, r
 		)
 		return
 	}
-	networkRecord, err = xenapi.Network.GetRecord(r.session, networkRef)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to get network record",
-			err.Error(),
-		)
-		err = cleanupVlanResource(r.session, networkRef)
+
+	tflog.Debug(ctx, "Creating Vlan...")
+	if data.Hosts.IsNull() {
+		var params vlanCreateParams
+		params, err = getVlanCreateParams(r.session, data, networkRef)
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Error cleaning up network resource",
+				"Unable to get vlan create params",
 				err.Error(),
 			)
+			err = cleanupVlanResource(r.session, networkRef)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error cleaning up network resource",
+					err.Error(),
+				)
+			}
+			return
 		}
-		return
+		_, err = xenapi.Pool.CreateVLANFromPIF(r.session, params.PifRef, params.NetworkRef, params.Tag)
+	} else {
+		err = updateVlanPIFs(ctx, r.session, networkRef, data)
 	}
-	err = updateVlanResourceModelComputed(ctx, networkRecord, &data)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to update the computed fields of vlanResourceModel",
+			"Unable to create vlan",
 			err.Error(),
 		)
 		err = cleanupVlanResource(r.session, networkRef)
@@ -186,12 +227,10 @@ func (r *vlanResource) Create(ctx context.Context, req resource.CreateRequest, r
 		}
 		return
 	}
-
-	tflog.Debug(ctx, "Creating Vlan...")
-	params, err := getVlanCreateParams(r.session, data, networkRef)
+	networkRecord, err = xenapi.Network.GetRecord(r.session, networkRef)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to get vlan create params",
+			"Unable to get network record",
 			err.Error(),
 		)
 		err = cleanupVlanResource(r.session, networkRef)
@@ -203,10 +242,10 @@ func (r *vlanResource) Create(ctx context.Context, req resource.CreateRequest, r
 		}
 		return
 	}
-	_, err = xenapi.Pool.CreateVLANFromPIF(r.session, params.PifRef, params.NetworkRef, params.Tag)
+	err = updateVlanResourceModelComputed(ctx, r.session, networkRecord, &data)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to create vlan",
+			"Unable to update the computed fields of vlanResourceModel",
 			err.Error(),
 		)
 		err = cleanupVlanResource(r.session, networkRef)
@@ -306,7 +345,7 @@ func (r *vlanResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		)
 		return
 	}
-	err = updateVlanResourceModelComputed(ctx, networkRecord, &plan)
+	err = updateVlanResourceModelComputed(ctx, r.session, networkRecord, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the computed fields of vlanResourceModel",
@@ -318,6 +357,32 @@ func (r *vlanResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan plans an update to create the VLAN when hosts which have the NIC are added to the pool
+func (r *vlanResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.session == nil {
+		return
+	}
+	var plan, state vlanResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Hosts.IsUnknown() || plan.NIC.IsUnknown() {
+		return
+	}
+
+	changed, err := isVlanHostsChanged(ctx, r.session, plan, state.PIFs)
+	if err != nil || !changed {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pifs"), types.MapUnknown(types.ObjectType{AttrTypes: vlanPIFModelAttrTypes}))...)
+}
+
 func (r *vlanResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data vlanResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
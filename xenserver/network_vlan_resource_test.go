@@ -21,6 +21,19 @@ resource "xenserver_network_vlan" "test_vlan" {
 `, name_label, name_description, mtu, tag, nic, extra_config)
 }
 
+func testAccVlanResourceHostsConfig() string {
+	return `
+data "xenserver_host" "host" {}
+
+resource "xenserver_network_vlan" "test_vlan" {
+	name_label = "test external network 2"
+	vlan_tag = 1
+	nic = "NIC 0"
+	hosts = [data.xenserver_host.host.data_items[0].uuid]
+}
+`
+}
+
 func TestAccVlanResource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -46,6 +59,7 @@ func TestAccVlanResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_network_vlan.test_vlan", "nic", "NIC 0"),
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("xenserver_network_vlan.test_vlan", "uuid"),
+					resource.TestCheckResourceAttrSet("xenserver_network_vlan.test_vlan", "pifs.%"),
 				),
 			},
 			// ImportState testing
@@ -80,6 +94,15 @@ func TestAccVlanResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_network_vlan.test_vlan", "nic", "NIC 0"),
 				),
 			},
+			// Restrict the VLAN to one host
+			{
+				Config: providerConfig + testAccVlanResourceHostsConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_network_vlan.test_vlan", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_network_vlan.test_vlan", "pifs.%", "1"),
+					resource.TestCheckResourceAttrPair("xenserver_network_vlan.test_vlan", "hosts.0", "data.xenserver_host.host", "data_items.0.uuid"),
+				),
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
@@ -0,0 +1,173 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &pbdDataSource{}
+	_ datasource.DataSourceWithConfigure = &pbdDataSource{}
+)
+
+// NewPBDDataSource is a helper function to simplify the provider implementation.
+func NewPBDDataSource() datasource.DataSource {
+	return &pbdDataSource{}
+}
+
+// pbdDataSource is the data source implementation.
+type pbdDataSource struct {
+	session *xenapi.Session
+}
+
+// Metadata returns the data source type name.
+func (d *pbdDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pbd"
+}
+
+// Schema defines the schema for the data source.
+func (d *pbdDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides information about the physical block devices (PBD), which connect the storage repositories to the hosts.",
+
+		Attributes: map[string]schema.Attribute{
+			"sr_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the storage repository which the PBD belongs to.",
+				Optional:            true,
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the host which the PBD connects to.",
+				Optional:            true,
+			},
+			"filter":       filterSchema(pbdRecordData{}),
+			"expect_count": expectCountSchema(),
+			"data_items": schema.ListNestedAttribute{
+				MarkdownDescription: "The return items of PBDs.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the PBD.",
+							Computed:            true,
+						},
+						"host": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the host which the PBD connects to.",
+							Computed:            true,
+						},
+						"sr_uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the storage repository which the PBD belongs to.",
+							Computed:            true,
+						},
+						"device_config": schema.MapAttribute{
+							MarkdownDescription: "The device config of the PBD, the passwords are stored as secrets, for example, `password_secret`.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"currently_attached": schema.BoolAttribute{
+							MarkdownDescription: "True if the storage repository is attached on the host.",
+							Computed:            true,
+						},
+						"other_config": schema.MapAttribute{
+							MarkdownDescription: "The additional configuration of the PBD.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *pbdDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.session = providerData.session
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *pbdDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data pbdDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pbdRecords, err := xenapi.PBD.GetAllRecords(d.session)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read PBD records",
+			err.Error(),
+		)
+		return
+	}
+
+	var pbdItems []pbdRecordData
+	for _, pbdRecord := range pbdRecords {
+		var pbdData pbdRecordData
+		err = updatePBDRecordData(ctx, d.session, pbdRecord, &pbdData)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to update PBD record data",
+				err.Error(),
+			)
+			return
+		}
+		if !data.SR.IsNull() && pbdData.SR.ValueString() != data.SR.ValueString() {
+			continue
+		}
+		if !data.Host.IsNull() && pbdData.Host.ValueString() != data.Host.ValueString() {
+			continue
+		}
+		matched, err := matchFilters(pbdData, data.Filter)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to filter PBD record data",
+				err.Error(),
+			)
+			return
+		}
+		if !matched {
+			continue
+		}
+		pbdItems = append(pbdItems, pbdData)
+	}
+
+	sort.Slice(pbdItems, func(i, j int) bool {
+		return pbdItems[i].UUID.ValueString() < pbdItems[j].UUID.ValueString()
+	})
+	err = checkExpectCount(data.ExpectCount, len(pbdItems))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unexpected number of PBDs",
+			err.Error(),
+		)
+		return
+	}
+	data.DataItems = pbdItems
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,51 @@
+package xenserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccPBDDataSourceConfig(extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_host" "host_data" {
+	is_coordinator = true
+}
+
+data "xenserver_pbd" "test_pbd_data" {
+	host = data.xenserver_host.host_data.data_items[0].uuid
+	%s
+}
+`, extra_config)
+}
+
+func TestAccPBDDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccPBDDataSourceConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.xenserver_pbd.test_pbd_data", "data_items.#"),
+					resource.TestCheckResourceAttrPair("data.xenserver_pbd.test_pbd_data", "data_items.0.host", "data.xenserver_host.host_data", "data_items.0.uuid"),
+				),
+			},
+			{
+				Config: providerConfig + testAccPBDDataSourceConfig(`
+	filter = [
+		{
+			name  = "currently_attached"
+			value = "true"
+		}
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.xenserver_pbd.test_pbd_data", "data_items.#"),
+					resource.TestCheckResourceAttr("data.xenserver_pbd.test_pbd_data", "data_items.0.currently_attached", "true"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,53 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+type pbdDataSourceModel struct {
+	SR          types.String    `tfsdk:"sr_uuid"`
+	Host        types.String    `tfsdk:"host"`
+	Filter      []filterObject  `tfsdk:"filter"`
+	ExpectCount types.Int64     `tfsdk:"expect_count"`
+	DataItems   []pbdRecordData `tfsdk:"data_items"`
+}
+
+type pbdRecordData struct {
+	UUID              types.String `tfsdk:"uuid"`
+	Host              types.String `tfsdk:"host"`
+	SR                types.String `tfsdk:"sr_uuid"`
+	DeviceConfig      types.Map    `tfsdk:"device_config"`
+	CurrentlyAttached types.Bool   `tfsdk:"currently_attached"`
+	OtherConfig       types.Map    `tfsdk:"other_config"`
+}
+
+func updatePBDRecordData(ctx context.Context, session *xenapi.Session, record xenapi.PBDRecord, data *pbdRecordData) error {
+	data.UUID = types.StringValue(record.UUID)
+	hostUUID, err := getUUIDFromHostRef(session, record.Host)
+	if err != nil {
+		return err
+	}
+	data.Host = types.StringValue(hostUUID)
+	srUUID, err := xenapi.SR.GetUUID(session, record.SR)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	data.SR = types.StringValue(srUUID)
+	var diags diag.Diagnostics
+	data.DeviceConfig, diags = types.MapValueFrom(ctx, types.StringType, record.DeviceConfig)
+	if diags.HasError() {
+		return errors.New("unable to read PBD device config")
+	}
+	data.CurrentlyAttached = types.BoolValue(record.CurrentlyAttached)
+	data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
+	if diags.HasError() {
+		return errors.New("unable to read PBD other config")
+	}
+	return nil
+}
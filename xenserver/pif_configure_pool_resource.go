@@ -0,0 +1,286 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource               = &pifConfigurePoolResource{}
+	_ resource.ResourceWithConfigure  = &pifConfigurePoolResource{}
+	_ resource.ResourceWithModifyPlan = &pifConfigurePoolResource{}
+)
+
+func NewPIFConfigurePoolResource() resource.Resource {
+	return &pifConfigurePoolResource{}
+}
+
+// pifConfigurePoolResource defines the resource implementation.
+type pifConfigurePoolResource struct {
+	session *xenapi.Session
+}
+
+func (r *pifConfigurePoolResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pif_configure_pool"
+}
+
+func (r *pifConfigurePoolResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "PIF configuration resource which is used to update the IPv4 parameters of the PIFs of a NIC on all the hosts in the pool consistently. \n\n Noted that no new PIF will be deployed when `terraform apply` is executed. Additionally, when it comes to `terraform destroy`, it actually has no effect on this resource.",
+		Attributes: map[string]schema.Attribute{
+			"nic": schema.StringAttribute{
+				MarkdownDescription: "The NIC of the PIFs on every host, for example, `\"NIC 1\"`, `\"Bond 0+1\"`." + "<br />" +
+					"The NIC on target XenServer environment can be found by the `xenserver_nic` data-source.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^NIC [0-9]+$|^Bond [0-9+]+$`),
+						`must be "NIC <index>" or "Bond <index>+<index>", eg. "NIC 0", "Bond 0+1"`,
+					),
+				},
+			},
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the interface in IP Address Configuration.",
+				Optional:            true,
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "The protocol define the primary address of the PIFs, for example, `\"None\"`, `\"DHCP\"`, `\"Static\"`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("None", "DHCP", "Static"),
+				},
+			},
+			"netmask": schema.StringAttribute{
+				MarkdownDescription: "The IP netmask of all the PIFs.",
+				Optional:            true,
+			},
+			"gateway": schema.StringAttribute{
+				MarkdownDescription: "The IP gateway of all the PIFs.",
+				Optional:            true,
+			},
+			"dns": schema.StringAttribute{
+				MarkdownDescription: "Comma separated list of the IP addresses of the DNS servers to use for all the PIFs.",
+				Optional:            true,
+			},
+			"hosts": schema.MapNestedAttribute{
+				MarkdownDescription: "The static IP settings of the PIF on each host, the key is the host UUID. The `netmask`, `gateway` and `dns` override the settings of all the PIFs." +
+					"\n\n-> **Note:** Either `hosts` or `ip_range` is required when `mode` is `\"Static\"`, the hosts not in `hosts` get the IP address from `ip_range`.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ip": schema.StringAttribute{
+							MarkdownDescription: "The IP address.",
+							Required:            true,
+						},
+						"netmask": schema.StringAttribute{
+							MarkdownDescription: "The IP netmask.",
+							Optional:            true,
+						},
+						"gateway": schema.StringAttribute{
+							MarkdownDescription: "The IP gateway.",
+							Optional:            true,
+						},
+						"dns": schema.StringAttribute{
+							MarkdownDescription: "Comma separated list of the IP addresses of the DNS servers to use.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"ip_range": schema.SingleNestedAttribute{
+				MarkdownDescription: "The IPv4 address range to allocate the static IP addresses from, the IP addresses are allocated in the order of the host UUID and kept for the host in the later apply. The IP addresses in use by other PIFs are skipped.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"start": schema.StringAttribute{
+						MarkdownDescription: "The first IP address of the range.",
+						Required:            true,
+					},
+					"end": schema.StringAttribute{
+						MarkdownDescription: "The last IP address of the range.",
+						Required:            true,
+					},
+				},
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRoot("netmask")),
+				},
+			},
+			"disallow_unplug": schema.BoolAttribute{
+				MarkdownDescription: "Set to `true` if you want to prevent the PIFs from being unplugged.",
+				Optional:            true,
+			},
+			"check_ip_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The duration in seconds to wait for the IP address of each PIF when `mode` is `\"DHCP\"`, default to be `60`. Set to `0` to skip the check.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(pifDefaultCheckIPTimeout),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"ip_addresses": schema.MapAttribute{
+				MarkdownDescription: "The IP address of the PIF on each host after the configuration, the key is the host UUID. The PIFs on the hosts which join the pool later are configured in the next apply.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the PIF configuration.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *pifConfigurePoolResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *pifConfigurePoolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data pifConfigurePoolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := pifConfigurePoolResourceModelCheck(data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error create xenserver_pif_configure_pool configuration",
+			err.Error(),
+		)
+		return
+	}
+	err = pifConfigurePoolResourceModelUpdate(ctx, r.session, &data, map[string]string{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update PIF configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *pifConfigurePoolResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data pifConfigurePoolResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := updatePIFConfigurePoolResourceModel(ctx, r.session, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of PIFConfigurePoolResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *pifConfigurePoolResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state pifConfigurePoolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := pifConfigurePoolResourceModelCheck(plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_pif_configure_pool configuration",
+			err.Error(),
+		)
+		return
+	}
+	// keep the IP addresses allocated from ip_range in the previous apply
+	allocated := make(map[string]string)
+	if !state.IPAddresses.IsNull() {
+		resp.Diagnostics.Append(state.IPAddresses.ElementsAs(ctx, &allocated, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	err = pifConfigurePoolResourceModelUpdate(ctx, r.session, &plan, allocated)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update PIF configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// ModifyPlan plans an update to configure the PIFs when hosts which have the NIC are added to the pool
+func (r *pifConfigurePoolResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.session == nil {
+		return
+	}
+	var plan, state pifConfigurePoolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.NIC.IsUnknown() || !plan.NIC.Equal(state.NIC) || state.IPAddresses.IsNull() {
+		return
+	}
+
+	changed, err := isPIFConfigurePoolHostsChanged(ctx, r.session, state)
+	if err != nil || !changed {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ip_addresses"), types.MapUnknown(types.StringType))...)
+}
+
+func (r *pifConfigurePoolResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "Don't recover the PIF configuration when destroy resource")
+}
@@ -0,0 +1,67 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccPIFConfigurePoolResourceConfig(mode string, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_pif_configure_pool" "pif_pool" {
+  nic  = "NIC 1"
+  mode = "%s"
+  %s
+}
+`, mode, extra_config)
+}
+
+func TestAccPIFConfigurePoolResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccPIFConfigurePoolResourceConfig("wrong-type", ""),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config:      providerConfig + testAccPIFConfigurePoolResourceConfig("Static", ""),
+				ExpectError: regexp.MustCompile(`"hosts" or "ip_range" is required when "mode" is "Static"`),
+			},
+			{
+				Config: providerConfig + testAccPIFConfigurePoolResourceConfig("DHCP", `
+  ip_range = {
+    start = "192.0.2.10"
+    end   = "192.0.2.20"
+  }
+  netmask = "255.255.255.0"`),
+				ExpectError: regexp.MustCompile(`"hosts" and "ip_range" are only supported when "mode" is "Static"`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccPIFConfigurePoolResourceConfig("Static", `
+  ip_range = {
+    start = "192.0.2.10"
+    end   = "192.0.2.20"
+  }
+  netmask = "255.255.255.0"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_pif_configure_pool.pif_pool", "mode", "Static"),
+					resource.TestCheckResourceAttr("xenserver_pif_configure_pool.pif_pool", "check_ip_timeout", "60"),
+					resource.TestCheckResourceAttrSet("xenserver_pif_configure_pool.pif_pool", "ip_addresses.%"),
+					resource.TestCheckResourceAttr("xenserver_pif_configure_pool.pif_pool", "id", "NIC 1"),
+				),
+			},
+			// Revert changes
+			{
+				Config: providerConfig + testAccPIFConfigurePoolResourceConfig("None", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_pif_configure_pool.pif_pool", "mode", "None"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"xenapi"
@@ -49,7 +52,7 @@ func (r *pifConfigureResource) Schema(_ context.Context, _ resource.SchemaReques
 				Optional:            true,
 			},
 			"interface": schema.SingleNestedAttribute{
-				MarkdownDescription: "The IP interface of the PIF. Currently only support IPv4.",
+				MarkdownDescription: "The IPv4 interface of the PIF.",
 				Optional:            true,
 				Attributes: map[string]schema.Attribute{
 					"name_label": schema.StringAttribute{
@@ -81,6 +84,52 @@ func (r *pifConfigureResource) Schema(_ context.Context, _ resource.SchemaReques
 					},
 				},
 			},
+			"ipv6": schema.SingleNestedAttribute{
+				MarkdownDescription: "The IPv6 interface of the PIF.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"mode": schema.StringAttribute{
+						MarkdownDescription: "The protocol define the IPv6 address of this PIF, for example, `\"None\"`, `\"DHCP\"`, `\"Static\"`, `\"Autoconf\"`.",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("None", "DHCP", "Static", "Autoconf"),
+						},
+					},
+					"ipv6": schema.StringAttribute{
+						MarkdownDescription: "The IPv6 address in CIDR format, for example, `\"2001:db8::1/64\"`.",
+						Optional:            true,
+					},
+					"gateway": schema.StringAttribute{
+						MarkdownDescription: "The IPv6 gateway.",
+						Optional:            true,
+					},
+					"dns": schema.StringAttribute{
+						MarkdownDescription: "Comma separated list of the IP addresses of the DNS servers to use.",
+						Optional:            true,
+					},
+				},
+			},
+			"primary_address_type": schema.StringAttribute{
+				MarkdownDescription: "The primary address type of the PIF, `\"IPv4\"` or `\"IPv6\"`. The management interface uses the address of this type.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("IPv4", "IPv6"),
+				},
+			},
+			"management": schema.BoolAttribute{
+				MarkdownDescription: "Set to `true` to move the management interface of the host to this PIF, the PIF should have an IP address of the primary address type configured. Setting it to `false` has no effect." +
+					"\n\n-> **Note:** The provider connection may be lost if the management IP address of the host which the provider connects to is changed.",
+				Optional: true,
+			},
+			"check_ip_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The duration in seconds to wait for the IP address when the mode of `interface` is `\"DHCP\"` or the mode of `ipv6` is `\"DHCP\"` or `\"Autoconf\"`, default to be `60`. Set to `0` to skip the check.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(pifDefaultCheckIPTimeout),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The test ID of the PIF.",
 				Computed:            true,
@@ -138,6 +187,10 @@ func (r *pifConfigureResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	// the timeout is not a PIF setting, keep the default after import
+	if data.CheckIPTimeout.IsNull() {
+		data.CheckIPTimeout = types.Int64Value(pifDefaultCheckIPTimeout)
+	}
 	data.ID = data.UUID
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -67,3 +67,53 @@ func TestAccPIFConfigureResource(t *testing.T) {
 		},
 	})
 }
+
+func testAccPIFConfigureResourceIPv6Config(mode string, extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_pif" "pif" {
+  device = "eth1"
+}
+
+resource "xenserver_pif_configure" "pif_ipv6" {
+  uuid = data.xenserver_pif.pif.data_items[0].uuid
+  ipv6 = {
+    mode = "%s"
+  }
+  %s
+}
+`, mode, extra_config)
+}
+
+func TestAccPIFConfigureResourceIPv6(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccPIFConfigureResourceIPv6Config("None", `primary_address_type = "IPv5"`),
+				ExpectError: regexp.MustCompile(`Attribute primary_address_type value must be one of`),
+			},
+			{
+				Config:      providerConfig + testAccPIFConfigureResourceIPv6Config("None", "check_ip_timeout = -1"),
+				ExpectError: regexp.MustCompile(`Attribute check_ip_timeout value must be at least 0`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccPIFConfigureResourceIPv6Config("Autoconf", "check_ip_timeout = 0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_pif_configure.pif_ipv6", "ipv6.mode", "Autoconf"),
+					resource.TestCheckResourceAttr("xenserver_pif_configure.pif_ipv6", "check_ip_timeout", "0"),
+				),
+			},
+			// Revert changes
+			{
+				Config: providerConfig + testAccPIFConfigureResourceIPv6Config("None", `primary_address_type = "IPv4"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_pif_configure.pif_ipv6", "ipv6.mode", "None"),
+					resource.TestCheckResourceAttr("xenserver_pif_configure.pif_ipv6", "primary_address_type", "IPv4"),
+					resource.TestCheckResourceAttr("xenserver_pif_configure.pif_ipv6", "check_ip_timeout", "60"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -164,7 +164,7 @@ func pifDataSchema() map[string]schema.Attribute {
 			ElementType:         types.StringType,
 		},
 		"igmp_snooping_status": schema.StringAttribute{
-			MarkdownDescription: "The IGMP snooping status of the corresponding network bridge.",
+			MarkdownDescription: "The IGMP snooping status of the corresponding network bridge, for example, `\"enabled\"`, `\"disabled\"`, `\"unknown\"`. It can be turned on or off by `igmp_snooping_enabled` of the `xenserver_pool` resource.",
 			Computed:            true,
 		},
 		"sriov_physical_pif_of": schema.ListAttribute{
@@ -200,6 +200,8 @@ func (d *pifDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 				MarkdownDescription: "The UUID of the virtual network to which this PIF is connected.",
 				Optional:            true,
 			},
+			"filter":       filterSchema(pifRecordData{}),
+			"expect_count": expectCountSchema(),
 			"data_items": schema.ListNestedAttribute{
 				MarkdownDescription: "The return items of physical network interfaces.",
 				Computed:            true,
@@ -278,12 +280,31 @@ func (d *pifDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 			)
 			return
 		}
+		matched, err := matchFilters(pifData, data.Filter)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to filter PIF record data",
+				err.Error(),
+			)
+			return
+		}
+		if !matched {
+			continue
+		}
 		pifItems = append(pifItems, pifData)
 	}
 
 	sort.Slice(pifItems, func(i, j int) bool {
 		return pifItems[i].UUID.ValueString() < pifItems[j].UUID.ValueString()
 	})
+	err = checkExpectCount(data.ExpectCount, len(pifItems))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unexpected number of PIFs",
+			err.Error(),
+		)
+		return
+	}
 	data.DataItems = pifItems
 
 	// Save data into Terraform state
@@ -27,6 +27,28 @@ data "xenserver_pif" "test_pif_data" {
 `
 }
 
+func testAccPifDataSourceFilterConfig() string {
+	return `
+data "xenserver_pif" "test_pif_data" {
+	filter = [
+		{
+			name  = "physical"
+			value = "true"
+		},
+		{
+			name  = "currently_attached"
+			value = "true"
+		},
+		{
+			name     = "device"
+			operator = "regex"
+			value    = "^eth0$"
+		}
+	]
+}
+`
+}
+
 func TestAccPifDataSource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -61,6 +83,14 @@ func TestAccPifDataSource(t *testing.T) {
 					},
 				),
 			},
+			{
+				Config: providerConfig + testAccPifDataSourceFilterConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.xenserver_pif.test_pif_data", "data_items.#"),
+					resource.TestCheckResourceAttr("data.xenserver_pif.test_pif_data", "data_items.0.device", "eth0"),
+					resource.TestCheckResourceAttr("data.xenserver_pif.test_pif_data", "data_items.0.physical", "true"),
+				),
+			},
 		},
 	})
 }
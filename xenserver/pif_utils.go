@@ -3,7 +3,10 @@ package xenserver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -16,10 +19,12 @@ import (
 
 // pifDataSourceModel describes the data source data model.
 type pifDataSourceModel struct {
-	Device     types.String    `tfsdk:"device"`
-	Management types.Bool      `tfsdk:"management"`
-	Network    types.String    `tfsdk:"network"`
-	DataItems  []pifRecordData `tfsdk:"data_items"`
+	Device      types.String    `tfsdk:"device"`
+	Management  types.Bool      `tfsdk:"management"`
+	Network     types.String    `tfsdk:"network"`
+	Filter      []filterObject  `tfsdk:"filter"`
+	ExpectCount types.Int64     `tfsdk:"expect_count"`
+	DataItems   []pifRecordData `tfsdk:"data_items"`
 }
 
 type pifRecordData struct {
@@ -181,12 +186,18 @@ func updatePIFRecordData(ctx context.Context, session *xenapi.Session, record xe
 }
 
 type pifConfigureResourceModel struct {
-	DisallowUnplug types.Bool   `tfsdk:"disallow_unplug"`
-	Interface      types.Object `tfsdk:"interface"`
-	UUID           types.String `tfsdk:"uuid"`
-	ID             types.String `tfsdk:"id"`
+	DisallowUnplug     types.Bool   `tfsdk:"disallow_unplug"`
+	Interface          types.Object `tfsdk:"interface"`
+	IPv6               types.Object `tfsdk:"ipv6"`
+	PrimaryAddressType types.String `tfsdk:"primary_address_type"`
+	Management         types.Bool   `tfsdk:"management"`
+	CheckIPTimeout     types.Int64  `tfsdk:"check_ip_timeout"`
+	UUID               types.String `tfsdk:"uuid"`
+	ID                 types.String `tfsdk:"id"`
 }
 
+const pifDefaultCheckIPTimeout = 60
+
 type InterfaceObject struct {
 	NameLabel types.String `tfsdk:"name_label"`
 	Mode      types.String `tfsdk:"mode"`
@@ -196,6 +207,13 @@ type InterfaceObject struct {
 	DNS       types.String `tfsdk:"dns"`
 }
 
+type IPv6InterfaceObject struct {
+	Mode    types.String `tfsdk:"mode"`
+	IPv6    types.String `tfsdk:"ipv6"`
+	Gateway types.String `tfsdk:"gateway"`
+	DNS     types.String `tfsdk:"dns"`
+}
+
 func getIPConfigurationMode(mode string) xenapi.IPConfigurationMode {
 	var value xenapi.IPConfigurationMode
 	switch mode {
@@ -211,6 +229,23 @@ func getIPConfigurationMode(mode string) xenapi.IPConfigurationMode {
 	return value
 }
 
+func getIpv6ConfigurationMode(mode string) xenapi.Ipv6ConfigurationMode {
+	var value xenapi.Ipv6ConfigurationMode
+	switch mode {
+	case "None":
+		value = xenapi.Ipv6ConfigurationModeNone
+	case "DHCP":
+		value = xenapi.Ipv6ConfigurationModeDHCP
+	case "Static":
+		value = xenapi.Ipv6ConfigurationModeStatic
+	case "Autoconf":
+		value = xenapi.Ipv6ConfigurationModeAutoconf
+	default:
+		value = xenapi.Ipv6ConfigurationModeUnrecognized
+	}
+	return value
+}
+
 func pifConfigureResourceModelUpdate(ctx context.Context, session *xenapi.Session, data pifConfigureResourceModel) error {
 	pifRef, err := xenapi.PIF.GetByUUID(session, data.UUID.ValueString())
 	if err != nil {
@@ -225,80 +260,172 @@ func pifConfigureResourceModelUpdate(ctx context.Context, session *xenapi.Sessio
 		}
 	}
 
-	if !data.Interface.IsNull() {
-		pifMetricsRef, err := xenapi.PIF.GetMetrics(session, pifRef)
+	if !data.Interface.IsNull() || !data.IPv6.IsNull() {
+		err = checkPIFConnected(session, pifRef, data.UUID.ValueString())
 		if err != nil {
-			return errors.New(err.Error())
+			return err
 		}
+	}
 
-		isPIFConnected, err := xenapi.PIFMetrics.GetCarrier(session, pifMetricsRef)
-		if err != nil {
-			return errors.New(err.Error())
+	if !data.Interface.IsNull() {
+		var interfaceObject InterfaceObject
+		diags := data.Interface.As(ctx, &interfaceObject, basetypes.ObjectAsOptions{})
+		if diags.HasError() {
+			return errors.New("unable to read PIF interface config")
 		}
 
-		if !isPIFConnected {
-			return errors.New("the PIF with uuid " + data.UUID.ValueString() + " is not connected")
+		err = reconfigurePIFIP(ctx, session, pifRef, interfaceObject, data.CheckIPTimeout.ValueInt64())
+		if err != nil {
+			return err
 		}
+	}
 
-		var interfaceObject InterfaceObject
-		diags := data.Interface.As(ctx, &interfaceObject, basetypes.ObjectAsOptions{})
+	if !data.IPv6.IsNull() {
+		var ipv6Object IPv6InterfaceObject
+		diags := data.IPv6.As(ctx, &ipv6Object, basetypes.ObjectAsOptions{})
 		if diags.HasError() {
-			return errors.New("unable to read PIF interface config")
+			return errors.New("unable to read PIF ipv6 config")
 		}
 
-		if !interfaceObject.NameLabel.IsNull() {
-			oc, err := xenapi.PIF.GetOtherConfig(session, pifRef)
+		mode := getIpv6ConfigurationMode(ipv6Object.Mode.ValueString())
+		ipv6 := ipv6Object.IPv6.ValueString()
+		gateway := ipv6Object.Gateway.ValueString()
+		dns := ipv6Object.DNS.ValueString()
+
+		tflog.Debug(ctx, "Reconfigure PIF IPv6 with mode: "+string(mode)+", ipv6: "+ipv6+", gateway: "+gateway+", dns: "+dns)
+		err = xenapi.PIF.ReconfigureIpv6(session, pifRef, mode, ipv6, gateway, dns)
+		if err != nil {
+			tflog.Error(ctx, "unable to update the PIF 'ipv6'")
+			return errors.New(err.Error())
+		}
+		if mode == xenapi.Ipv6ConfigurationModeDHCP || mode == xenapi.Ipv6ConfigurationModeAutoconf {
+			err := checkPIFHasIP(ctx, session, pifRef, data.CheckIPTimeout.ValueInt64(), true)
 			if err != nil {
-				return errors.New(err.Error())
+				return err
 			}
+		}
+	}
 
-			oc["management_purpose"] = interfaceObject.NameLabel.ValueString()
+	if !data.PrimaryAddressType.IsNull() {
+		err = xenapi.PIF.SetPrimaryAddressType(session, pifRef, xenapi.PrimaryAddressType(data.PrimaryAddressType.ValueString()))
+		if err != nil {
+			tflog.Error(ctx, "unable to update the PIF 'primary_address_type'")
+			return errors.New(err.Error())
+		}
+	}
 
-			err = xenapi.PIF.SetOtherConfig(session, pifRef, oc)
+	if data.Management.ValueBool() {
+		isManagement, err := xenapi.PIF.GetManagement(session, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if !isManagement {
+			tflog.Debug(ctx, "Move the host management interface to PIF "+data.UUID.ValueString())
+			err = xenapi.Host.ManagementReconfigure(session, pifRef)
 			if err != nil {
+				tflog.Error(ctx, "unable to update the PIF 'management'")
 				return errors.New(err.Error())
 			}
 		}
+	}
+
+	return nil
+}
+
+func checkPIFConnected(session *xenapi.Session, pifRef xenapi.PIFRef, uuid string) error {
+	pifMetricsRef, err := xenapi.PIF.GetMetrics(session, pifRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	isPIFConnected, err := xenapi.PIFMetrics.GetCarrier(session, pifMetricsRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
 
-		mode := getIPConfigurationMode(interfaceObject.Mode.ValueString())
-		ip := interfaceObject.IP.ValueString()
-		netmask := interfaceObject.Netmask.ValueString()
-		gateway := interfaceObject.Gateway.ValueString()
-		dns := interfaceObject.DNS.ValueString()
+	if !isPIFConnected {
+		return errors.New("the PIF with uuid " + uuid + " is not connected")
+	}
+	return nil
+}
 
-		tflog.Debug(ctx, "Reconfigure PIF IP with mode: "+string(mode)+", ip: "+ip+", netmask: "+netmask+", gateway: "+gateway+", dns: "+dns)
-		err = xenapi.PIF.ReconfigureIP(session, pifRef, mode, ip, netmask, gateway, dns)
+// reconfigurePIFIP sets the IPv4 configuration of the PIF and waits for the IP address in DHCP mode
+func reconfigurePIFIP(ctx context.Context, session *xenapi.Session, pifRef xenapi.PIFRef, interfaceObject InterfaceObject, timeout int64) error {
+	if !interfaceObject.NameLabel.IsNull() {
+		oc, err := xenapi.PIF.GetOtherConfig(session, pifRef)
 		if err != nil {
-			tflog.Error(ctx, "unable to update the PIF 'interface'")
 			return errors.New(err.Error())
 		}
-		if string(mode) == "DHCP" {
-			err := checkPIFHasIP(ctx, session, pifRef)
-			if err != nil {
-				return err
-			}
+
+		oc["management_purpose"] = interfaceObject.NameLabel.ValueString()
+
+		err = xenapi.PIF.SetOtherConfig(session, pifRef, oc)
+		if err != nil {
+			return errors.New(err.Error())
 		}
 	}
 
+	mode := getIPConfigurationMode(interfaceObject.Mode.ValueString())
+	ip := interfaceObject.IP.ValueString()
+	netmask := interfaceObject.Netmask.ValueString()
+	gateway := interfaceObject.Gateway.ValueString()
+	dns := interfaceObject.DNS.ValueString()
+
+	tflog.Debug(ctx, "Reconfigure PIF IP with mode: "+string(mode)+", ip: "+ip+", netmask: "+netmask+", gateway: "+gateway+", dns: "+dns)
+	err := xenapi.PIF.ReconfigureIP(session, pifRef, mode, ip, netmask, gateway, dns)
+	if err != nil {
+		tflog.Error(ctx, "unable to update the PIF 'interface'")
+		return errors.New(err.Error())
+	}
+	if string(mode) == "DHCP" {
+		err := checkPIFHasIP(ctx, session, pifRef, timeout, false)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func checkPIFHasIP(ctx context.Context, session *xenapi.Session, ref xenapi.PIFRef) error {
+func getPIFIPs(session *xenapi.Session, ref xenapi.PIFRef, ipv6 bool) ([]string, error) {
+	if ipv6 {
+		ips, err := xenapi.PIF.GetIPv6(session, ref)
+		if err != nil {
+			return ips, errors.New(err.Error())
+		}
+		// the IPv6 addresses are in CIDR format, eg. "2001:db8::1/64"
+		for i, ip := range ips {
+			ips[i] = strings.Split(ip, "/")[0]
+		}
+		return ips, nil
+	}
+	ip, err := xenapi.PIF.GetIP(session, ref)
+	if err != nil {
+		return []string{}, errors.New(err.Error())
+	}
+	return []string{ip}, nil
+}
+
+func checkPIFHasIP(ctx context.Context, session *xenapi.Session, ref xenapi.PIFRef, timeout int64, ipv6 bool) error {
+	if timeout == 0 {
+		return nil
+	}
 	// set timeout channel to check if IP address is available
-	timeoutChan := time.After(time.Duration(60) * time.Second)
+	timeoutChan := time.After(time.Duration(timeout) * time.Second)
 	for {
 		select {
 		case <-timeoutChan:
-			return errors.New("get PIF IP timeout in 60 seconds, please check if the interface is connected")
+			return fmt.Errorf("get PIF IP timeout in %d seconds, please check if the interface is connected", timeout)
 		default:
-			ip, err := xenapi.PIF.GetIP(session, ref)
+			ips, err := getPIFIPs(session, ref, ipv6)
 			if err != nil {
 				tflog.Error(ctx, "unable to get the PIF IP")
-				return errors.New(err.Error())
+				return err
 			}
-			if isValidIpAddress(net.ParseIP(ip)) {
-				tflog.Debug(ctx, "PIF IP is available: "+ip)
-				return nil
+			for _, ip := range ips {
+				if isValidIpAddress(net.ParseIP(ip)) {
+					tflog.Debug(ctx, "PIF IP is available: "+ip)
+					return nil
+				}
 			}
 
 			tflog.Debug(ctx, "-----> Retry get PIF IP")
@@ -306,3 +433,318 @@ func checkPIFHasIP(ctx context.Context, session *xenapi.Session, ref xenapi.PIFR
 		}
 	}
 }
+
+type pifConfigurePoolResourceModel struct {
+	NIC            types.String `tfsdk:"nic"`
+	NameLabel      types.String `tfsdk:"name_label"`
+	Mode           types.String `tfsdk:"mode"`
+	Netmask        types.String `tfsdk:"netmask"`
+	Gateway        types.String `tfsdk:"gateway"`
+	DNS            types.String `tfsdk:"dns"`
+	Hosts          types.Map    `tfsdk:"hosts"`
+	IPRange        types.Object `tfsdk:"ip_range"`
+	DisallowUnplug types.Bool   `tfsdk:"disallow_unplug"`
+	CheckIPTimeout types.Int64  `tfsdk:"check_ip_timeout"`
+	IPAddresses    types.Map    `tfsdk:"ip_addresses"`
+	ID             types.String `tfsdk:"id"`
+}
+
+type pifHostIPObject struct {
+	IP      types.String `tfsdk:"ip"`
+	Netmask types.String `tfsdk:"netmask"`
+	Gateway types.String `tfsdk:"gateway"`
+	DNS     types.String `tfsdk:"dns"`
+}
+
+type pifIPRangeObject struct {
+	Start types.String `tfsdk:"start"`
+	End   types.String `tfsdk:"end"`
+}
+
+func pifConfigurePoolResourceModelCheck(data pifConfigurePoolResourceModel) error {
+	isStatic := data.Mode.ValueString() == "Static"
+	if isStatic && data.Hosts.IsNull() && data.IPRange.IsNull() {
+		return errors.New(`"hosts" or "ip_range" is required when "mode" is "Static"`)
+	}
+	if !isStatic && (!data.Hosts.IsNull() || !data.IPRange.IsNull()) {
+		return errors.New(`"hosts" and "ip_range" are only supported when "mode" is "Static"`)
+	}
+	return nil
+}
+
+func ipv4ToUint32(address string) (uint32, error) {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return 0, errors.New("invalid IPv4 address " + address)
+	}
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3]), nil
+}
+
+func uint32ToIPv4(value uint32) string {
+	return net.IPv4(byte(value>>24), byte(value>>16), byte(value>>8), byte(value)).String()
+}
+
+// getPIFIPsInUse returns the IP addresses used by the PIFs which aren't reconfigured by the resource
+func getPIFIPsInUse(session *xenapi.Session, hostPIFs map[string]xenapi.PIFRef) ([]string, error) {
+	var ips []string
+	pifRecords, err := xenapi.PIF.GetAllRecords(session)
+	if err != nil {
+		return ips, errors.New(err.Error())
+	}
+	reconfigured := make(map[xenapi.PIFRef]bool)
+	for _, pifRef := range hostPIFs {
+		reconfigured[pifRef] = true
+	}
+	for pifRef, pifRecord := range pifRecords {
+		if pifRecord.IP == "" || reconfigured[pifRef] {
+			continue
+		}
+		ips = append(ips, pifRecord.IP)
+	}
+	return ips, nil
+}
+
+// allocatePIFIPs assigns an IP address to every host, the IP address in "hosts" takes precedence,
+// the IP address allocated in the previous apply is kept, then the free IP address in "ip_range" is
+// allocated in the order of the host UUID, the IP addresses in use by other PIFs are skipped
+func allocatePIFIPs(ctx context.Context, hostUUIDs []string, data pifConfigurePoolResourceModel, allocated map[string]string, inUse []string) (map[string]pifHostIPObject, error) {
+	hostIPs := make(map[string]pifHostIPObject)
+	if !data.Hosts.IsNull() {
+		diags := data.Hosts.ElementsAs(ctx, &hostIPs, false)
+		if diags.HasError() {
+			return hostIPs, errors.New("unable to access PIF hosts config")
+		}
+		for hostUUID := range hostIPs {
+			if !slices.Contains(hostUUIDs, hostUUID) {
+				return hostIPs, errors.New("unable to find host " + hostUUID + " in the pool")
+			}
+		}
+	}
+	if data.IPRange.IsNull() {
+		for _, hostUUID := range hostUUIDs {
+			if _, ok := hostIPs[hostUUID]; !ok {
+				return hostIPs, errors.New("no IP address is set for host " + hostUUID + ` in "hosts"`)
+			}
+		}
+		return hostIPs, nil
+	}
+
+	var ipRange pifIPRangeObject
+	diags := data.IPRange.As(ctx, &ipRange, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return hostIPs, errors.New("unable to access PIF ip_range config")
+	}
+	start, err := ipv4ToUint32(ipRange.Start.ValueString())
+	if err != nil {
+		return hostIPs, err
+	}
+	end, err := ipv4ToUint32(ipRange.End.ValueString())
+	if err != nil {
+		return hostIPs, err
+	}
+	if start > end {
+		return hostIPs, errors.New(`the "start" of "ip_range" should not be greater than the "end"`)
+	}
+	used := make(map[string]bool)
+	for _, ip := range inUse {
+		used[ip] = true
+	}
+	for _, hostIP := range hostIPs {
+		used[hostIP.IP.ValueString()] = true
+	}
+	inRange := func(ip string) bool {
+		value, err := ipv4ToUint32(ip)
+		return err == nil && value >= start && value <= end
+	}
+
+	sortedUUIDs := slices.Clone(hostUUIDs)
+	slices.Sort(sortedUUIDs)
+	var pending []string
+	for _, hostUUID := range sortedUUIDs {
+		if _, ok := hostIPs[hostUUID]; ok {
+			continue
+		}
+		ip, ok := allocated[hostUUID]
+		if ok && inRange(ip) && !used[ip] {
+			hostIPs[hostUUID] = pifHostIPObject{IP: types.StringValue(ip)}
+			used[ip] = true
+			continue
+		}
+		pending = append(pending, hostUUID)
+	}
+	next := start
+	for _, hostUUID := range pending {
+		for next <= end && used[uint32ToIPv4(next)] {
+			next++
+		}
+		if next > end {
+			return hostIPs, fmt.Errorf(`not enough free IP addresses in "ip_range" for %d hosts, the IP addresses in use by other PIFs are skipped`, len(hostUUIDs))
+		}
+		ip := uint32ToIPv4(next)
+		hostIPs[hostUUID] = pifHostIPObject{IP: types.StringValue(ip)}
+		used[ip] = true
+	}
+	return hostIPs, nil
+}
+
+// getPIFInterfaceForHost merges the host specific IP settings with the common settings
+func getPIFInterfaceForHost(data pifConfigurePoolResourceModel, hostIP pifHostIPObject) InterfaceObject {
+	interfaceObject := InterfaceObject{
+		NameLabel: data.NameLabel,
+		Mode:      data.Mode,
+		IP:        hostIP.IP,
+		Netmask:   data.Netmask,
+		Gateway:   data.Gateway,
+		DNS:       data.DNS,
+	}
+	if !hostIP.Netmask.IsNull() && !hostIP.Netmask.IsUnknown() {
+		interfaceObject.Netmask = hostIP.Netmask
+	}
+	if !hostIP.Gateway.IsNull() && !hostIP.Gateway.IsUnknown() {
+		interfaceObject.Gateway = hostIP.Gateway
+	}
+	if !hostIP.DNS.IsNull() && !hostIP.DNS.IsUnknown() {
+		interfaceObject.DNS = hostIP.DNS
+	}
+	return interfaceObject
+}
+
+// getPIFsByHostForNIC returns the PIFs of the NIC keyed by the host UUID, and the host UUIDs in the order of the PIFs
+func getPIFsByHostForNIC(session *xenapi.Session, nic string) (map[string]xenapi.PIFRef, []string, error) {
+	hostPIFs := make(map[string]xenapi.PIFRef)
+	var hostUUIDs []string
+	pifRefs, err := getPifRefsForNIC(session, nic)
+	if err != nil {
+		return hostPIFs, hostUUIDs, err
+	}
+	if len(pifRefs) == 0 {
+		return hostPIFs, hostUUIDs, errors.New("unable to find PIF for NIC " + nic)
+	}
+	for _, pifRef := range pifRefs {
+		hostRef, err := xenapi.PIF.GetHost(session, pifRef)
+		if err != nil {
+			return hostPIFs, hostUUIDs, errors.New(err.Error())
+		}
+		hostUUID, err := xenapi.Host.GetUUID(session, hostRef)
+		if err != nil {
+			return hostPIFs, hostUUIDs, errors.New(err.Error())
+		}
+		hostPIFs[hostUUID] = pifRef
+		hostUUIDs = append(hostUUIDs, hostUUID)
+	}
+	return hostPIFs, hostUUIDs, nil
+}
+
+// isPIFConfigurePoolHostsChanged returns true if there are hosts with the NIC which are not configured yet, for example, the hosts joined the pool later
+func isPIFConfigurePoolHostsChanged(ctx context.Context, session *xenapi.Session, data pifConfigurePoolResourceModel) (bool, error) {
+	_, hostUUIDs, err := getPIFsByHostForNIC(session, data.NIC.ValueString())
+	if err != nil {
+		return false, err
+	}
+	ipAddresses := make(map[string]string)
+	diags := data.IPAddresses.ElementsAs(ctx, &ipAddresses, false)
+	if diags.HasError() {
+		return false, errors.New("unable to access pif_configure_pool ip_addresses")
+	}
+	for _, hostUUID := range hostUUIDs {
+		if _, ok := ipAddresses[hostUUID]; !ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updatePIFConfigurePoolResourceModel refreshes the IP addresses of the configured PIFs, the hosts which have left the pool are removed
+func updatePIFConfigurePoolResourceModel(ctx context.Context, session *xenapi.Session, data *pifConfigurePoolResourceModel) error {
+	hostPIFs, _, err := getPIFsByHostForNIC(session, data.NIC.ValueString())
+	if err != nil {
+		return err
+	}
+	configured := make(map[string]string)
+	if !data.IPAddresses.IsNull() {
+		diags := data.IPAddresses.ElementsAs(ctx, &configured, false)
+		if diags.HasError() {
+			return errors.New("unable to access pif_configure_pool ip_addresses")
+		}
+	}
+	ipAddresses := make(map[string]string)
+	for hostUUID := range configured {
+		pifRef, ok := hostPIFs[hostUUID]
+		if !ok {
+			continue
+		}
+		ip, err := xenapi.PIF.GetIP(session, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		ipAddresses[hostUUID] = ip
+	}
+	var diags diag.Diagnostics
+	data.IPAddresses, diags = types.MapValueFrom(ctx, types.StringType, ipAddresses)
+	if diags.HasError() {
+		return errors.New("unable to update data for pif_configure_pool ip_addresses")
+	}
+	data.ID = data.NIC
+	return nil
+}
+
+// pifConfigurePoolResourceModelUpdate reconfigures the PIF of the NIC on every host in the pool
+func pifConfigurePoolResourceModelUpdate(ctx context.Context, session *xenapi.Session, data *pifConfigurePoolResourceModel, allocated map[string]string) error {
+	hostPIFs, hostUUIDs, err := getPIFsByHostForNIC(session, data.NIC.ValueString())
+	if err != nil {
+		return err
+	}
+
+	hostIPs := make(map[string]pifHostIPObject)
+	if data.Mode.ValueString() == "Static" {
+		inUse, err := getPIFIPsInUse(session, hostPIFs)
+		if err != nil {
+			return err
+		}
+		hostIPs, err = allocatePIFIPs(ctx, hostUUIDs, *data, allocated, inUse)
+		if err != nil {
+			return err
+		}
+	}
+
+	ipAddresses := make(map[string]string)
+	for _, hostUUID := range hostUUIDs {
+		pifRef := hostPIFs[hostUUID]
+		pifUUID, err := xenapi.PIF.GetUUID(session, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if !data.DisallowUnplug.IsNull() {
+			err = xenapi.PIF.SetDisallowUnplug(session, pifRef, data.DisallowUnplug.ValueBool())
+			if err != nil {
+				tflog.Error(ctx, "unable to update the PIF 'disallow_unplug'")
+				return errors.New(err.Error())
+			}
+		}
+		err = checkPIFConnected(session, pifRef, pifUUID)
+		if err != nil {
+			return err
+		}
+		hostIP, ok := hostIPs[hostUUID]
+		if !ok {
+			hostIP = pifHostIPObject{IP: types.StringNull()}
+		}
+		err = reconfigurePIFIP(ctx, session, pifRef, getPIFInterfaceForHost(*data, hostIP), data.CheckIPTimeout.ValueInt64())
+		if err != nil {
+			return err
+		}
+		ip, err := xenapi.PIF.GetIP(session, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		ipAddresses[hostUUID] = ip
+	}
+
+	var diags diag.Diagnostics
+	data.IPAddresses, diags = types.MapValueFrom(ctx, types.StringType, ipAddresses)
+	if diags.HasError() {
+		return errors.New("unable to update data for pif_configure_pool ip_addresses")
+	}
+	data.ID = data.NIC
+	return nil
+}
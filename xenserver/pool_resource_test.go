@@ -149,3 +149,43 @@ func TestAccPoolManagementNetwork(t *testing.T) {
 	// sleep 30s to wait for supporters and management network back to enable
 	time.Sleep(30 * time.Second)
 }
+
+func igmpSnoopingParams(name_label string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "xenserver_pool" "pool" {
+    name_label   = "%s"
+    igmp_snooping_enabled = %t
+}
+
+data "xenserver_network" "network" {
+    depends_on = [xenserver_pool.pool]
+}
+`, name_label, enabled)
+}
+
+func TestAccPoolIGMPSnooping(t *testing.T) {
+	// skip test if TEST_POOL is not set
+	if os.Getenv("TEST_POOL") == "" {
+		t.Skip("Skipping TestAccPoolIGMPSnooping test due to TEST_POOL not set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + igmpSnoopingParams("Test Pool D", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_pool.pool", "igmp_snooping_enabled", "true"),
+					resource.TestCheckResourceAttrSet("data.xenserver_network.network", "data_items.0.igmp_snooping_status"),
+				),
+			},
+			{
+				Config: providerConfig + igmpSnoopingParams("Test Pool D", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_pool.pool", "igmp_snooping_enabled", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -24,6 +24,7 @@ type poolResourceModel struct {
 	NameDescription       types.String `tfsdk:"name_description"`
 	DefaultSRUUID         types.String `tfsdk:"default_sr"`
 	ManagementNetworkUUID types.String `tfsdk:"management_network"`
+	IGMPSnoopingEnabled   types.Bool   `tfsdk:"igmp_snooping_enabled"`
 	JoinSupporters        types.Set    `tfsdk:"join_supporters"`
 	EjectSupporters       types.Set    `tfsdk:"eject_supporters"`
 	UUID                  types.String `tfsdk:"uuid"`
@@ -41,6 +42,7 @@ type poolParams struct {
 	NameDescription       string
 	DefaultSRUUID         string
 	ManagementNetworkUUID string
+	IGMPSnoopingEnabled   *bool
 }
 
 func PoolSchema() map[string]schema.Attribute {
@@ -69,6 +71,12 @@ func PoolSchema() map[string]schema.Attribute {
 			Optional: true,
 			Computed: true,
 		},
+		"igmp_snooping_enabled": schema.BoolAttribute{
+			MarkdownDescription: "Whether IGMP snooping is enabled on all the networks of the pool, default inherited from the pool." + "<br />" +
+				"The IGMP snooping status of the networks and PIFs can be found by the `xenserver_network` and `xenserver_pif` data-sources.",
+			Optional: true,
+			Computed: true,
+		},
 		"join_supporters": schema.SetNestedAttribute{
 			MarkdownDescription: "The set of pool supporters which will join the pool." +
 				"\n\n-> **Note:** 1. It would raise error if a supporter is in both join_supporters and eject_supporters.<br>" +
@@ -122,6 +130,10 @@ func getPoolParams(plan poolResourceModel) poolParams {
 	if !plan.ManagementNetworkUUID.IsUnknown() {
 		params.ManagementNetworkUUID = plan.ManagementNetworkUUID.ValueString()
 	}
+	if !plan.IGMPSnoopingEnabled.IsUnknown() && !plan.IGMPSnoopingEnabled.IsNull() {
+		enabled := plan.IGMPSnoopingEnabled.ValueBool()
+		params.IGMPSnoopingEnabled = &enabled
+	}
 
 	return params
 }
@@ -366,6 +378,13 @@ func setPool(session *xenapi.Session, poolRef xenapi.PoolRef, poolParams poolPar
 		}
 	}
 
+	if poolParams.IGMPSnoopingEnabled != nil {
+		err = xenapi.Pool.SetIgmpSnoopingEnabled(session, poolRef, *poolParams.IGMPSnoopingEnabled)
+		if err != nil {
+			return errors.New("unable to set pool igmp_snooping_enabled. " + err.Error())
+		}
+	}
+
 	if poolParams.ManagementNetworkUUID != "" {
 		networkRef, err := xenapi.Network.GetByUUID(session, poolParams.ManagementNetworkUUID)
 		if err != nil {
@@ -422,6 +441,7 @@ func updatePoolResourceModelComputed(session *xenapi.Session, record xenapi.Pool
 	data.UUID = types.StringValue(record.UUID)
 	data.ID = types.StringValue(record.UUID)
 	data.NameDescription = types.StringValue(record.NameDescription)
+	data.IGMPSnoopingEnabled = types.BoolValue(record.IgmpSnoopingEnabled)
 
 	data.DefaultSRUUID = types.StringValue("")
 	if string(record.DefaultSR) != "OpaqueRef:NULL" {
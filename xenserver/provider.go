@@ -203,6 +203,19 @@ func (p *xsProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewVlanResource,
 		NewSnapshotResource,
 		NewPIFConfigureResource,
+		NewVMApplianceResource,
+		NewBondResource,
+		NewNetworkResource,
+		NewTunnelResource,
+		NewSriovResource,
+		NewPIFConfigurePoolResource,
+		NewHostResource,
+		NewISCSIResource,
+		NewHBAResource,
+		NewClusterResource,
+		NewVDIImportResource,
+		NewVDICbtExportResource,
+		NewISOResource,
 	}
 }
 
@@ -214,6 +227,10 @@ func (p *xsProvider) DataSources(_ context.Context) []func() datasource.DataSour
 		NewNetworkDataSource,
 		NewNICDataSource,
 		NewHostDataSource,
+		NewISCSIProbeDataSource,
+		NewHBALUNDataSource,
+		NewPBDDataSource,
+		NewVDIDataSource,
 	}
 }
 
@@ -8,8 +8,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"xenapi"
@@ -75,6 +77,14 @@ func (r *snapshotResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					Attributes: vdiSchema(),
 				},
 			},
+			"vdis": schema.MapAttribute{
+				MarkdownDescription: "The snapshot VDIs of the VM disks, the key is the UUID of the VM disk and the value is the UUID of its snapshot VDI. It can be used as the baseline of `xenserver_vdi_cbt_export`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"uuid": schema.StringAttribute{
 				MarkdownDescription: "The UUID of the snapshot.",
 				Computed:            true,
@@ -62,6 +62,7 @@ func TestAccSnapshotResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("xenserver_snapshot.test_snapshot", "name_label", "Test snapshot A"),
 					resource.TestCheckResourceAttr("xenserver_snapshot.test_snapshot", "with_memory", "false"),
+					resource.TestCheckResourceAttr("xenserver_snapshot.test_snapshot", "vdis.%", "1"),
 					resource.TestCheckResourceAttrSet("xenserver_snapshot.test_snapshot", "uuid"),
 				),
 			},
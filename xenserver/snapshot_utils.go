@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"xenapi"
@@ -16,6 +17,7 @@ type snapshotResourceModel struct {
 	WithMemory types.Bool   `tfsdk:"with_memory"`
 	Revert     types.Bool   `tfsdk:"revert"`
 	RevertVDIs types.Set    `tfsdk:"revert_vdis"`
+	VDIs       types.Map    `tfsdk:"vdis"`
 	UUID       types.String `tfsdk:"uuid"`
 	ID         types.String `tfsdk:"id"`
 }
@@ -63,8 +65,17 @@ func updateSnapshotResourceModelComputed(ctx context.Context, session *xenapi.Se
 	} else {
 		data.WithMemory = types.BoolValue(false)
 	}
+	vdis, err := getSnapshotVDIs(session, record)
+	if err != nil {
+		return err
+	}
+	var diags diag.Diagnostics
+	data.VDIs, diags = types.MapValueFrom(ctx, types.StringType, vdis)
+	if diags.HasError() {
+		return errors.New("unable to get snapshot VDIs")
+	}
 	// update the revert_vdis only when revert is true
-	var vdiDataList []vdiResourceModel
+	var vdiDataList []vdiModel
 	if !data.Revert.IsNull() && data.Revert.ValueBool() {
 		vdiRefs, err := getAllDiskTypeVDIs(session, record.SnapshotOf)
 		if err != nil {
@@ -83,7 +94,7 @@ func updateSnapshotResourceModelComputed(ctx context.Context, session *xenapi.Se
 			if diags.HasError() {
 				return errors.New("unable to access VDI other config")
 			}
-			vdiData := vdiResourceModel{
+			vdiData := vdiModel{
 				NameLabel:       types.StringValue(vdiRecord.NameLabel),
 				NameDescription: types.StringValue(vdiRecord.NameDescription),
 				SR:              types.StringValue(srUUID),
@@ -93,12 +104,13 @@ func updateSnapshotResourceModelComputed(ctx context.Context, session *xenapi.Se
 				Type:            types.StringValue(string(vdiRecord.Type)),
 				Sharable:        types.BoolValue(vdiRecord.Sharable),
 				ReadOnly:        types.BoolValue(vdiRecord.ReadOnly),
+				CbtEnabled:      types.BoolValue(vdiRecord.CbtEnabled),
 				OtherConfig:     otherConfig,
 			}
 			vdiDataList = append(vdiDataList, vdiData)
 		}
 	}
-	setValue, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: vdiResourceModelAttrTypes}, vdiDataList)
+	setValue, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: vdiModelAttrTypes}, vdiDataList)
 	if diags.HasError() {
 		return errors.New("unable to get VDI set value")
 	}
@@ -107,6 +119,32 @@ func updateSnapshotResourceModelComputed(ctx context.Context, session *xenapi.Se
 	return nil
 }
 
+// getSnapshotVDIs returns the map of the VDI UUID to its snapshot VDI UUID in the snapshot
+func getSnapshotVDIs(session *xenapi.Session, record xenapi.VMRecord) (map[string]string, error) {
+	vdis := make(map[string]string)
+	for _, vbdRef := range record.VBDs {
+		vbdRecord, err := xenapi.VBD.GetRecord(session, vbdRef)
+		if err != nil {
+			return vdis, errors.New(err.Error())
+		}
+		if vbdRecord.Type != xenapi.VbdTypeDisk || string(vbdRecord.VDI) == "OpaqueRef:NULL" {
+			continue
+		}
+		vdiRecord, err := xenapi.VDI.GetRecord(session, vbdRecord.VDI)
+		if err != nil {
+			return vdis, errors.New(err.Error())
+		}
+		vdiUUID, err := getUUIDFromVDIRef(session, vdiRecord.SnapshotOf)
+		if err != nil {
+			return vdis, err
+		}
+		if vdiUUID != "" {
+			vdis[vdiUUID] = vdiRecord.UUID
+		}
+	}
+	return vdis, nil
+}
+
 func snapshotResourceModelUpdateCheck(plan snapshotResourceModel, state snapshotResourceModel) error {
 	if plan.VM != state.VM {
 		return errors.New(`"vm_uuid" doesn't expected to be updated`)
@@ -0,0 +1,313 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &hbaResource{}
+	_ resource.ResourceWithConfigure   = &hbaResource{}
+	_ resource.ResourceWithImportState = &hbaResource{}
+	_ resource.ResourceWithModifyPlan  = &hbaResource{}
+)
+
+func NewHBAResource() resource.Resource {
+	return &hbaResource{}
+}
+
+// hbaResource defines the resource implementation.
+type hbaResource struct {
+	session *xenapi.Session
+}
+
+func (r *hbaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sr_hba"
+}
+
+func (r *hbaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a Fibre Channel or SAS hardware HBA (lvmohba or gfs2) storage repository resource.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the HBA storage repository.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the HBA storage repository, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the storage repository, default to be `\"lvmohba\"`, for example, `\"lvmohba\"`, `\"gfs2\"`." + "<br />" +
+					"`\"gfs2\"` creates a thin-provisioned clustered storage repository, which requires all the hosts in the pool to be in the cluster created by the `xenserver_cluster` resource." +
+					"\n\n-> **Note:** `type` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("lvmohba"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("lvmohba", gfs2SRType),
+				},
+			},
+			"scsi_id": schema.StringAttribute{
+				MarkdownDescription: "The SCSI ID of the LUN to create the storage repository on." + "<br />" +
+					"The LUNs on target XenServer environment can be found by the `xenserver_hba_lun` data-source." +
+					"\n\n-> **Note:** `scsi_id` is not allowed to be updated.",
+				Required: true,
+			},
+			"force": schema.BoolAttribute{
+				MarkdownDescription: "Set to `true` to format the LUN even if it already holds an SR, default to be `false`. Used when creating the SR." +
+					"\n\n!> **Warning:** All the data of the existing SR on the LUN will be lost.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"mode":             srModeSchema(),
+			"destroy_behavior": srDestroyBehaviorSchema(),
+			"pbds":             srPBDsSchema(),
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the HBA storage repository.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the HBA storage repository.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *hbaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *hbaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data hbaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating HBA SR...")
+	params, err := getHBACreateParams(r.session, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR create params",
+			err.Error(),
+		)
+		return
+	}
+	srRef, err := createOrAttachSRResource(r.session, params, data.Mode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create SR",
+			err.Error(),
+		)
+		return
+	}
+	srRecord, _, err := getSRRecordAndPBDRecord(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR or PBD record",
+			err.Error(),
+		)
+		err = cleanupSRResource(r.session, srRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up SR resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateHBAResourceModelComputed(ctx, r.session, srRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of HBAResourceModel",
+			err.Error(),
+		)
+		err = cleanupSRResource(r.session, srRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up SR resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	tflog.Debug(ctx, "HBA SR created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read data from State, retrieve the resource's information, update to State
+// terraform import
+func (r *hbaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data hbaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	srRef, err := xenapi.SR.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR ref in Read stage",
+			err.Error(),
+		)
+		return
+	}
+	srRecord, pbdRecord, err := getSRRecordAndPBDRecord(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR or PBD record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateHBAResourceModel(ctx, r.session, srRecord, pbdRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of HBAResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *hbaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state hbaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := hbaResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_sr_hba configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	srRef, err := xenapi.SR.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR ref in Update stage",
+			err.Error(),
+		)
+		return
+	}
+	err = hbaResourceModelUpdate(r.session, srRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update HBA SR resource",
+			err.Error(),
+		)
+		return
+	}
+	err = repairSRPBDs(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to plug SR PBDs",
+			err.Error(),
+		)
+		return
+	}
+	srRecord, _, err := getSRRecordAndPBDRecord(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR or PBD record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateHBAResourceModelComputed(ctx, r.session, srRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of HBAResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// ModifyPlan plans an update to plug the detached PBDs and create the PBDs for the new pool members
+func (r *hbaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifySRPBDsPlan(ctx, r.session, req, resp)
+}
+
+func (r *hbaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data hbaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	srRef, err := xenapi.SR.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR ref in Delete stage",
+			err.Error(),
+		)
+		return
+	}
+	err = deleteSRResource(r.session, srRef, data.DestroyBehavior.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete HBA SR",
+			err.Error(),
+		)
+		return
+	}
+}
+
+func (r *hbaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
@@ -0,0 +1,152 @@
+package xenserver
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccHBAResourceConfig(name_label string, name_description string, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_sr_hba" "test_hba" {
+	name_label       = "%s"
+	name_description = "%s"
+	scsi_id          = "%s"
+	%s
+}
+`, name_label, name_description, os.Getenv("HBA_SCSI_ID"), extra_config)
+}
+
+func testAccHBALUNDataSourceConfig() string {
+	return fmt.Sprintf(`
+data "xenserver_hba_lun" "test_hba_lun" {
+	scsi_id = "%s"
+}
+`, os.Getenv("HBA_SCSI_ID"))
+}
+
+func TestAccHBAResource(t *testing.T) {
+	if os.Getenv("HBA_SCSI_ID") == "" {
+		t.Skip("Skipping TestAccHBAResource test due to HBA_SCSI_ID not set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// LUN data source testing
+			{
+				Config: providerConfig + testAccHBALUNDataSourceConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xenserver_hba_lun.test_hba_lun", "data_items.0.scsi_id", os.Getenv("HBA_SCSI_ID")),
+					resource.TestCheckResourceAttrSet("data.xenserver_hba_lun.test_hba_lun", "data_items.0.size"),
+				),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccHBAResourceConfig("Test HBA storage repository", "", "force = true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_sr_hba.test_hba", "name_label", "Test HBA storage repository"),
+					resource.TestCheckResourceAttr("xenserver_sr_hba.test_hba", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_sr_hba.test_hba", "scsi_id", os.Getenv("HBA_SCSI_ID")),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_sr_hba.test_hba", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_sr_hba.test_hba",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccHBAResourceConfig("Test HBA storage repository 2", "Test HBA Description", "force = true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_sr_hba.test_hba", "name_label", "Test HBA storage repository 2"),
+					resource.TestCheckResourceAttr("xenserver_sr_hba.test_hba", "name_description", "Test HBA Description"),
+				),
+			},
+			// The SR is forgotten but still on the LUN after destroy
+			{
+				Config: providerConfig,
+			},
+			{
+				Config:      providerConfig + testAccHBAResourceConfig("Test HBA storage repository", "", ""),
+				ExpectError: regexp.MustCompile(`already holds the SR`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestParseHBAProbeDevlist(t *testing.T) {
+	devlist := `<?xml version="1.0" ?>
+<Devlist>
+	<Adapter>
+		<host>host1</host>
+		<name>qla2xxx</name>
+	</Adapter>
+	<BlockDevice>
+		<path>
+			/dev/sdb
+		</path>
+		<SCSIid>
+			360a98000534b4f4e46704c76692d6d33
+		</SCSIid>
+		<vendor>
+			NETAPP
+		</vendor>
+		<serial>
+			SKONFpLvi-m3
+		</serial>
+		<size>
+			107374182400
+		</size>
+		<adapter>
+			1
+		</adapter>
+		<lun>
+			0
+		</lun>
+	</BlockDevice>
+</Devlist>
+`
+	testCases := []struct {
+		name   string
+		result string
+	}{
+		{"probe result", devlist},
+		{"error description", "API error: code 1, message SR_BACKEND_FAILURE_107, data [ The SCSIid parameter is missing or incorrect " + devlist + "]"},
+		{"escaped error description", "SR_BACKEND_FAILURE_107: [ ; The SCSIid parameter is missing or incorrect; " + strings.NewReplacer("\n", `\n`, "\t", `\t`, `"`, `\"`).Replace(devlist) + "]"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			devices, err := parseHBAProbeDevlist(testCase.result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(devices) != 1 {
+				t.Fatalf("expected 1 block device, got %d", len(devices))
+			}
+			var lunData hbaLUNItemData
+			err = updateHBALUNItemData("host-uuid", devices[0], &lunData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lunData.SCSIid.ValueString() != "360a98000534b4f4e46704c76692d6d33" || lunData.Path.ValueString() != "/dev/sdb" ||
+				lunData.Vendor.ValueString() != "NETAPP" || lunData.LUN.ValueString() != "0" || lunData.Size.ValueInt64() != 107374182400 {
+				t.Fatalf("unexpected HBA LUN data: %+v", lunData)
+			}
+		})
+	}
+
+	_, err := parseHBAProbeDevlist("API error: code 1, message SR_BACKEND_FAILURE_107, data [ The SCSIid parameter is missing or incorrect ]")
+	if err == nil {
+		t.Fatal("expected error when the devlist is missing")
+	}
+}
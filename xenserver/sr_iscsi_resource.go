@@ -0,0 +1,379 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &iscsiResource{}
+	_ resource.ResourceWithConfigure   = &iscsiResource{}
+	_ resource.ResourceWithImportState = &iscsiResource{}
+	_ resource.ResourceWithModifyPlan  = &iscsiResource{}
+)
+
+func NewISCSIResource() resource.Resource {
+	return &iscsiResource{}
+}
+
+// iscsiResource defines the resource implementation.
+type iscsiResource struct {
+	session *xenapi.Session
+}
+
+func (r *iscsiResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sr_iscsi"
+}
+
+func (r *iscsiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides an iSCSI (lvmoiscsi or gfs2) storage repository resource. The IQNs and LUNs of the target can be found by the `xenserver_iscsi_probe` data-source." +
+			"\n\n-> **Note:** The target is probed from the pool coordinator during `terraform plan`, the plan fails if the target isn't reachable.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the iSCSI storage repository.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the iSCSI storage repository, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the storage repository, default to be `\"lvmoiscsi\"`, for example, `\"lvmoiscsi\"`, `\"gfs2\"`." + "<br />" +
+					"`\"gfs2\"` creates a thin-provisioned clustered storage repository, which requires all the hosts in the pool to be in the cluster created by the `xenserver_cluster` resource." +
+					"\n\n-> **Note:** `type` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("lvmoiscsi"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("lvmoiscsi", gfs2SRType),
+				},
+			},
+			"target": schema.StringAttribute{
+				MarkdownDescription: "The IP address or hostname of the iSCSI target, multiple addresses can be separated by comma for multipath." +
+					"\n\n-> **Note:** `target` is not allowed to be updated.",
+				Required: true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The port of the iSCSI target, default to be `3260`." +
+					"\n\n-> **Note:** `port` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(iscsiDefaultPort),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"target_iqn": schema.StringAttribute{
+				MarkdownDescription: "The IQN of the iSCSI target, for example, `\"iqn.2010-01.com.example:storage\"`. Set to `\"*\"` to use all the IQNs of the target." +
+					"\n\n-> **Note:** `target_iqn` is not allowed to be updated.",
+				Required: true,
+			},
+			"scsi_id": schema.StringAttribute{
+				MarkdownDescription: "The SCSI ID of the LUN to create the storage repository on." +
+					"\n\n-> **Note:** `scsi_id` is not allowed to be updated.",
+				Required: true,
+			},
+			"chap_username": schema.StringAttribute{
+				MarkdownDescription: "The CHAP username to authenticate with the iSCSI target. Used when creating the SR.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("chap_password")),
+				},
+			},
+			"chap_password": schema.StringAttribute{
+				MarkdownDescription: "The CHAP password to authenticate with the iSCSI target. Used when creating the SR." +
+					"\n\n-> **Note:** This password will be stored in terraform state file, follow document [Sensitive values in state](https://developer.hashicorp.com/terraform/tutorials/configuration-language/sensitive-variables#sensitive-values-in-state) to protect your sensitive data.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("chap_username")),
+				},
+			},
+			"multipath": schema.BoolAttribute{
+				MarkdownDescription: "Whether the storage repository uses multipathing, default to be `false`. Multipathing should be enabled on all the hosts before creating the SR." +
+					"\n\n-> **Note:** `multipath` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"mode":             srModeSchema(),
+			"destroy_behavior": srDestroyBehaviorSchema(),
+			"pbds":             srPBDsSchema(),
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the iSCSI storage repository.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the iSCSI storage repository.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *iscsiResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+// ModifyPlan checks the iSCSI target is reachable before creating the SR,
+// and plans an update to plug the detached PBDs and create the PBDs for the new pool members
+func (r *iscsiResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when destroying the resource
+	if req.Plan.Raw.IsNull() || r.session == nil {
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		modifySRPBDsPlan(ctx, r.session, req, resp)
+		return
+	}
+
+	// check the iSCSI target is reachable when creating the resource
+	var plan iscsiResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Target.IsUnknown() || plan.Port.IsUnknown() || plan.ChapUsername.IsUnknown() || plan.ChapPassword.IsUnknown() {
+		return
+	}
+
+	deviceConfig := getISCSIDeviceConfig(plan.Target.ValueString(), plan.Port.ValueInt64(), "", "", plan.ChapUsername.ValueString(), plan.ChapPassword.ValueString())
+	_, err := probeISCSI(r.session, deviceConfig)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("target"),
+			"Unable to reach the iSCSI target",
+			err.Error(),
+		)
+	}
+}
+
+func (r *iscsiResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data iscsiResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating iSCSI SR...")
+	params, err := getISCSICreateParams(r.session, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR create params",
+			err.Error(),
+		)
+		return
+	}
+	srRef, err := createOrAttachSRResource(r.session, params, data.Mode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create SR",
+			err.Error(),
+		)
+		return
+	}
+	srRecord, _, err := getSRRecordAndPBDRecord(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR or PBD record",
+			err.Error(),
+		)
+		err = cleanupSRResource(r.session, srRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up SR resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateISCSIResourceModelComputed(ctx, r.session, srRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of ISCSIResourceModel",
+			err.Error(),
+		)
+		err = cleanupSRResource(r.session, srRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up SR resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	tflog.Debug(ctx, "iSCSI SR created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read data from State, retrieve the resource's information, update to State
+// terraform import
+func (r *iscsiResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data iscsiResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	srRef, err := xenapi.SR.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR ref in Read stage",
+			err.Error(),
+		)
+		return
+	}
+	srRecord, pbdRecord, err := getSRRecordAndPBDRecord(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR or PBD record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateISCSIResourceModel(ctx, r.session, srRecord, pbdRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of ISCSIResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *iscsiResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state iscsiResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := iscsiResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_sr_iscsi configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	srRef, err := xenapi.SR.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR ref in Update stage",
+			err.Error(),
+		)
+		return
+	}
+	err = iscsiResourceModelUpdate(r.session, srRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update iSCSI SR resource",
+			err.Error(),
+		)
+		return
+	}
+	err = repairSRPBDs(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to plug SR PBDs",
+			err.Error(),
+		)
+		return
+	}
+	srRecord, _, err := getSRRecordAndPBDRecord(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR or PBD record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateISCSIResourceModelComputed(ctx, r.session, srRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of ISCSIResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iscsiResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data iscsiResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	srRef, err := xenapi.SR.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get SR ref in Delete stage",
+			err.Error(),
+		)
+		return
+	}
+	err = deleteSRResource(r.session, srRef, data.DestroyBehavior.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete iSCSI SR",
+			err.Error(),
+		)
+		return
+	}
+}
+
+func (r *iscsiResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
@@ -0,0 +1,101 @@
+package xenserver
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccISCSIResourceConfig(name_label string, name_description string, target string, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_sr_iscsi" "test_iscsi" {
+	name_label       = "%s"
+	name_description = "%s"
+	target           = "%s"
+	target_iqn       = "%s"
+	scsi_id          = "%s"
+	%s
+}
+`, name_label, name_description, target, os.Getenv("ISCSI_TARGET_IQN"), os.Getenv("ISCSI_SCSI_ID"), extra_config)
+}
+
+func testAccISCSIProbeDataSourceConfig(target_iqn string) string {
+	return fmt.Sprintf(`
+data "xenserver_iscsi_probe" "test_iscsi_probe" {
+	target     = "%s"
+	target_iqn = "%s"
+}
+`, os.Getenv("ISCSI_TARGET"), target_iqn)
+}
+
+func TestAccISCSIResource(t *testing.T) {
+	if os.Getenv("ISCSI_TARGET") == "" {
+		t.Skip("Skipping TestAccISCSIResource test due to ISCSI_TARGET not set")
+	}
+
+	target := os.Getenv("ISCSI_TARGET")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccISCSIResourceConfig("Test iSCSI storage repository", "", "192.0.2.1", ""),
+				ExpectError: regexp.MustCompile(`Unable to reach the iSCSI target`),
+			},
+			{
+				Config:      providerConfig + testAccISCSIResourceConfig("Test iSCSI storage repository", "", target, `chap_username = "user"`),
+				ExpectError: regexp.MustCompile(`Attribute "chap_password" must be specified when "chap_username" is\s+specified`),
+			},
+			// Probe testing
+			{
+				Config: providerConfig + testAccISCSIProbeDataSourceConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("data.xenserver_iscsi_probe.test_iscsi_probe", "iqns.*", os.Getenv("ISCSI_TARGET_IQN")),
+				),
+			},
+			{
+				Config: providerConfig + testAccISCSIProbeDataSourceConfig(os.Getenv("ISCSI_TARGET_IQN")),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("data.xenserver_iscsi_probe.test_iscsi_probe", "scsi_ids.*", os.Getenv("ISCSI_SCSI_ID")),
+				),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccISCSIResourceConfig("Test iSCSI storage repository", "", target, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "name_label", "Test iSCSI storage repository"),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "target", target),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "port", "3260"),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "target_iqn", os.Getenv("ISCSI_TARGET_IQN")),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "scsi_id", os.Getenv("ISCSI_SCSI_ID")),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "multipath", "false"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("xenserver_sr_iscsi.test_iscsi", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_sr_iscsi.test_iscsi",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			{
+				Config:      providerConfig + testAccISCSIResourceConfig("Test iSCSI storage repository", "", target, "port = 3261"),
+				ExpectError: regexp.MustCompile(`"port" doesn't expected to be updated`),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccISCSIResourceConfig("Test iSCSI storage repository 2", "Test iSCSI Description", target, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "name_label", "Test iSCSI storage repository 2"),
+					resource.TestCheckResourceAttr("xenserver_sr_iscsi.test_iscsi", "name_description", "Test iSCSI Description"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
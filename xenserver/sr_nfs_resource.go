@@ -22,6 +22,7 @@ var (
 	_ resource.Resource                = &nfsResource{}
 	_ resource.ResourceWithConfigure   = &nfsResource{}
 	_ resource.ResourceWithImportState = &nfsResource{}
+	_ resource.ResourceWithModifyPlan  = &nfsResource{}
 )
 
 func NewNFSResource() resource.Resource {
@@ -84,6 +85,9 @@ func (r *nfsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Computed: true,
 				Default:  stringdefault.StaticString(""),
 			},
+			"mode":             srModeSchema(),
+			"destroy_behavior": srDestroyBehaviorSchema(),
+			"pbds":             srPBDsSchema(),
 			"uuid": schema.StringAttribute{
 				MarkdownDescription: "The UUID of the NFS storage repository.",
 				Computed:            true,
@@ -135,7 +139,7 @@ func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		)
 		return
 	}
-	srRef, err := createSRResource(r.session, params)
+	srRef, err := createOrAttachSRResource(r.session, params, data.Mode.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create SR",
@@ -158,7 +162,7 @@ func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		}
 		return
 	}
-	err = updateNFSResourceModelComputed(srRecord, pbdRecord, &data)
+	err = updateNFSResourceModelComputed(ctx, r.session, srRecord, pbdRecord, &data)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the computed fields of NFSResourceModel",
@@ -204,7 +208,7 @@ func (r *nfsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		)
 		return
 	}
-	err = updateNFSResourceModel(srRecord, pbdRecord, &data)
+	err = updateNFSResourceModel(ctx, r.session, srRecord, pbdRecord, &data)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the fields of NFSResourceModel",
@@ -254,6 +258,14 @@ func (r *nfsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		)
 		return
 	}
+	err = repairSRPBDs(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to plug SR PBDs",
+			err.Error(),
+		)
+		return
+	}
 	srRecord, pbdRecord, err := getSRRecordAndPBDRecord(r.session, srRef)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -262,7 +274,7 @@ func (r *nfsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		)
 		return
 	}
-	err = updateNFSResourceModelComputed(srRecord, pbdRecord, &plan)
+	err = updateNFSResourceModelComputed(ctx, r.session, srRecord, pbdRecord, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the computed fields of NFSResourceModel",
@@ -274,6 +286,11 @@ func (r *nfsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan plans an update to plug the detached PBDs and create the PBDs for the new pool members
+func (r *nfsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifySRPBDsPlan(ctx, r.session, req, resp)
+}
+
 func (r *nfsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data nfsResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -289,7 +306,7 @@ func (r *nfsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		)
 		return
 	}
-	err = cleanupSRResource(r.session, srRef)
+	err = deleteSRResource(r.session, srRef, data.DestroyBehavior.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to delete NFS SR",
@@ -43,6 +43,7 @@ func TestAccNFSResource(t *testing.T) {
 					// Verify dynamic values have any value set in the state.
 
 					resource.TestCheckResourceAttrSet("xenserver_sr_nfs.test_nfs", "uuid"),
+					resource.TestCheckResourceAttrSet("xenserver_sr_nfs.test_nfs", "pbds.%"),
 				),
 			},
 			// ImportState testing
@@ -74,10 +75,32 @@ func TestAccNFSResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "storage_location", os.Getenv("NFS_SERVER")+":"+os.Getenv("NFS_SERVER_PATH")),
 					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "version", "3"),
 					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "advanced_options", ""),
+					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "mode", "create"),
+					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "destroy_behavior", "forget"),
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("xenserver_sr_nfs.test_nfs", "uuid"),
 				),
 			},
+			// Detach the SR and attach it again
+			{
+				Config: providerConfig + testAccNFSResourceConfig("Test NFS storage repository 2", "Test NFS Description", "3", storage_location, `destroy_behavior = "detach"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "destroy_behavior", "detach"),
+				),
+			},
+			{
+				Config: providerConfig,
+			},
+			{
+				Config: providerConfig + testAccNFSResourceConfig("Test NFS storage repository 3", "", "3", storage_location, `mode = "attach"
+	destroy_behavior = "destroy"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "name_label", "Test NFS storage repository 3"),
+					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "mode", "attach"),
+					resource.TestCheckResourceAttr("xenserver_sr_nfs.test_nfs", "destroy_behavior", "destroy"),
+					resource.TestCheckResourceAttrSet("xenserver_sr_nfs.test_nfs", "uuid"),
+				),
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
@@ -93,6 +116,11 @@ func TestAccNFSISOResource(t *testing.T) {
 				Config:      providerConfig + testAccNFSResourceConfig("Test NFS ISO library", "", "3", storage_location, "type = \"other-type\""),
 				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
 			},
+			{
+				Config: providerConfig + testAccNFSResourceConfig("Test NFS ISO library", "", "3", storage_location, `type = "iso"
+	mode = "attach"`),
+				ExpectError: regexp.MustCompile(`Invalid SR mode`),
+			},
 			{
 				Config: providerConfig + testAccNFSResourceConfig("Test NFS ISO library", "", "3", storage_location, "type = \"iso\""),
 				Check: resource.ComposeAggregateTestCheckFunc(
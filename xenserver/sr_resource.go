@@ -24,6 +24,7 @@ var (
 	_ resource.Resource                = &srResource{}
 	_ resource.ResourceWithConfigure   = &srResource{}
 	_ resource.ResourceWithImportState = &srResource{}
+	_ resource.ResourceWithModifyPlan  = &srResource{}
 )
 
 func NewSRResource() resource.Resource {
@@ -54,7 +55,8 @@ func (r *srResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Default:             stringdefault.StaticString(""),
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "The type of the storage repository, default to be `\"dummy\"`." +
+				MarkdownDescription: "The type of the storage repository, default to be `\"dummy\"`." + "<br />" +
+					"`\"gfs2\"` requires all the hosts in the pool to be in the cluster created by the `xenserver_cluster` resource." +
 					"\n\n-> **Note:** `type` is not allowed to be updated.",
 				Optional: true,
 				Computed: true,
@@ -95,6 +97,9 @@ func (r *srResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Optional: true,
 				Computed: true,
 			},
+			"mode":             srModeSchema(),
+			"destroy_behavior": srDestroyBehaviorSchema(),
+			"pbds":             srPBDsSchema(),
 			"uuid": schema.StringAttribute{
 				MarkdownDescription: "The UUID of the storage repository.",
 				Computed:            true,
@@ -146,7 +151,7 @@ func (r *srResource) Create(ctx context.Context, req resource.CreateRequest, res
 		)
 		return
 	}
-	srRef, err := createSRResource(r.session, params)
+	srRef, err := createOrAttachSRResource(r.session, params, data.Mode.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create SR",
@@ -265,6 +270,14 @@ func (r *srResource) Update(ctx context.Context, req resource.UpdateRequest, res
 		)
 		return
 	}
+	err = repairSRPBDs(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to plug SR PBDs",
+			err.Error(),
+		)
+		return
+	}
 	srRecord, pbdRecord, err := getSRRecordAndPBDRecord(r.session, srRef)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -285,6 +298,11 @@ func (r *srResource) Update(ctx context.Context, req resource.UpdateRequest, res
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan plans an update to plug the detached PBDs and create the PBDs for the new pool members
+func (r *srResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifySRPBDsPlan(ctx, r.session, req, resp)
+}
+
 func (r *srResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data srResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -300,7 +318,7 @@ func (r *srResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 		)
 		return
 	}
-	err = cleanupSRResource(r.session, srRef)
+	err = deleteSRResource(r.session, srRef, data.DestroyBehavior.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to delete NFS SR",
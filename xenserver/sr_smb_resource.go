@@ -22,6 +22,7 @@ var (
 	_ resource.Resource                = &smbResource{}
 	_ resource.ResourceWithConfigure   = &smbResource{}
 	_ resource.ResourceWithImportState = &smbResource{}
+	_ resource.ResourceWithModifyPlan  = &smbResource{}
 )
 
 func NewSMBResource() resource.Resource {
@@ -78,6 +79,9 @@ func (r *smbResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Optional:  true,
 				Sensitive: true,
 			},
+			"mode":             srModeSchema(),
+			"destroy_behavior": srDestroyBehaviorSchema(),
+			"pbds":             srPBDsSchema(),
 			"uuid": schema.StringAttribute{
 				MarkdownDescription: "The UUID of the SMB storage repository.",
 				Computed:            true,
@@ -129,7 +133,7 @@ func (r *smbResource) Create(ctx context.Context, req resource.CreateRequest, re
 		)
 		return
 	}
-	srRef, err := createSRResource(r.session, params)
+	srRef, err := createOrAttachSRResource(r.session, params, data.Mode.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create SR",
@@ -152,7 +156,7 @@ func (r *smbResource) Create(ctx context.Context, req resource.CreateRequest, re
 		}
 		return
 	}
-	err = updateSMBResourceModelComputed(srRecord, &data)
+	err = updateSMBResourceModelComputed(ctx, r.session, srRecord, &data)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the computed fields of SMBResourceModel",
@@ -198,7 +202,7 @@ func (r *smbResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		)
 		return
 	}
-	err = updateSMBResourceModel(srRecord, pbdRecord, &data)
+	err = updateSMBResourceModel(ctx, r.session, srRecord, pbdRecord, &data)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the fields of SMBResourceModel",
@@ -248,6 +252,14 @@ func (r *smbResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		)
 		return
 	}
+	err = repairSRPBDs(r.session, srRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to plug SR PBDs",
+			err.Error(),
+		)
+		return
+	}
 	srRecord, _, err := getSRRecordAndPBDRecord(r.session, srRef)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -256,7 +268,7 @@ func (r *smbResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		)
 		return
 	}
-	err = updateSMBResourceModelComputed(srRecord, &plan)
+	err = updateSMBResourceModelComputed(ctx, r.session, srRecord, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update the computed fields of SMBResourceModel",
@@ -268,6 +280,11 @@ func (r *smbResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan plans an update to plug the detached PBDs and create the PBDs for the new pool members
+func (r *smbResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifySRPBDsPlan(ctx, r.session, req, resp)
+}
+
 func (r *smbResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data smbResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -283,7 +300,7 @@ func (r *smbResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		)
 		return
 	}
-	err = cleanupSRResource(r.session, srRef)
+	err = deleteSRResource(r.session, srRef, data.DestroyBehavior.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to delete SMB SR",
@@ -2,11 +2,22 @@ package xenserver
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"xenapi"
@@ -131,6 +142,9 @@ type srResourceModel struct {
 	SmConfig        types.Map    `tfsdk:"sm_config"`
 	DeviceConfig    types.Map    `tfsdk:"device_config"`
 	Host            types.String `tfsdk:"host"`
+	Mode            types.String `tfsdk:"mode"`
+	DestroyBehavior types.String `tfsdk:"destroy_behavior"`
+	PBDs            types.Map    `tfsdk:"pbds"`
 	UUID            types.String `tfsdk:"uuid"`
 	ID              types.String `tfsdk:"id"`
 }
@@ -155,6 +169,12 @@ func getSRCreateParams(ctx context.Context, session *xenapi.Session, data srReso
 		return params, err
 	}
 	params.Host = coordinatorRef
+	if params.TypeKey == gfs2SRType {
+		err = checkClusterReady(session)
+		if err != nil {
+			return params, err
+		}
+	}
 	if !data.Host.IsUnknown() {
 		hostRef, err := xenapi.Host.GetByUUID(session, data.Host.ValueString())
 		if err != nil {
@@ -183,6 +203,7 @@ func getSRRecordAndPBDRecord(session *xenapi.Session, srRef xenapi.SRRef) (xenap
 
 func updateSRResourceModel(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *srResourceModel) error {
 	data.NameLabel = types.StringValue(srRecord.NameLabel)
+	updateSRModeAndDestroyBehavior(&data.Mode, &data.DestroyBehavior)
 
 	return updateSRResourceModelComputed(ctx, session, srRecord, pbdRecord, data)
 }
@@ -216,6 +237,12 @@ func updateSRResourceModelComputed(ctx context.Context, session *xenapi.Session,
 		return errors.New("unable to access PBD device config")
 	}
 
+	pbds, err := getSRPBDsValue(ctx, session, srRecord.PBDs)
+	if err != nil {
+		return err
+	}
+	data.PBDs = pbds
+
 	return nil
 }
 
@@ -299,12 +326,75 @@ func unplugPBDs(session *xenapi.Session, pbdRefs []xenapi.PBDRef) error {
 	return nil
 }
 
-func cleanupSRResource(session *xenapi.Session, ref xenapi.SRRef) error {
-	pbdRefs, err := xenapi.SR.GetPBDs(session, ref)
+// unplugClusteredSRPBDs unplugs the PBDs of the clustered SR in the order required by the cluster, the PBDs on the
+// non-coordinator hosts first and the PBD on the coordinator last. The cluster host on each host should be enabled,
+// the cluster state isn't changed, so nothing is unplugged if any of the cluster hosts is disabled or missing.
+func unplugClusteredSRPBDs(session *xenapi.Session, pbdRefs []xenapi.PBDRef) error {
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return err
+	}
+	clusterHostRecords, err := xenapi.ClusterHost.GetAllRecords(session)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	enabledHosts := make(map[xenapi.HostRef]bool)
+	for _, clusterHostRecord := range clusterHostRecords {
+		enabledHosts[clusterHostRecord.Host] = clusterHostRecord.Enabled
+	}
+
+	var nonCoordinatorPBDs, coordinatorPBDs []xenapi.PBDRef
+	var disabledHosts []string
+	for _, pbdRef := range pbdRefs {
+		pbdRecord, err := xenapi.PBD.GetRecord(session, pbdRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if !pbdRecord.CurrentlyAttached {
+			continue
+		}
+		if !enabledHosts[pbdRecord.Host] {
+			hostName, err := xenapi.Host.GetNameLabel(session, pbdRecord.Host)
+			if err != nil {
+				return errors.New(err.Error())
+			}
+			disabledHosts = append(disabledHosts, hostName)
+		}
+		if pbdRecord.Host == coordinatorRef {
+			coordinatorPBDs = append(coordinatorPBDs, pbdRef)
+		} else {
+			nonCoordinatorPBDs = append(nonCoordinatorPBDs, pbdRef)
+		}
+	}
+	if len(disabledHosts) > 0 {
+		return fmt.Errorf("unable to detach the clustered SR, the cluster hosts on %s are disabled or missing, enable them in the cluster first", strings.Join(disabledHosts, ", "))
+	}
+
+	for _, pbdRef := range append(nonCoordinatorPBDs, coordinatorPBDs...) {
+		err = xenapi.PBD.Unplug(session, pbdRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// detachSRResource unplugs the PBDs of the SR from all the hosts, the SR is kept in the pool
+func detachSRResource(session *xenapi.Session, ref xenapi.SRRef) error {
+	srRecord, err := xenapi.SR.GetRecord(session, ref)
 	if err != nil {
 		return errors.New(err.Error())
 	}
-	err = unplugPBDs(session, pbdRefs)
+	if srRecord.Clustered {
+		return unplugClusteredSRPBDs(session, srRecord.PBDs)
+	}
+	return unplugPBDs(session, srRecord.PBDs)
+}
+
+// cleanupSRResource detaches the SR from all the hosts and forgets it, the PBDs of the clustered SR are unplugged
+// while the cluster is still running, so the SR is forgotten before the cluster is destroyed
+func cleanupSRResource(session *xenapi.Session, ref xenapi.SRRef) error {
+	err := detachSRResource(session, ref)
 	if err != nil {
 		return err
 	}
@@ -315,32 +405,60 @@ func cleanupSRResource(session *xenapi.Session, ref xenapi.SRRef) error {
 	return nil
 }
 
-func createSRResource(session *xenapi.Session, params srCreateParams) (xenapi.SRRef, error) {
-	var srRef xenapi.SRRef
-	// Create secret for password
+// deleteSRResource removes the SR resource according to the destroy behavior
+func deleteSRResource(session *xenapi.Session, ref xenapi.SRRef, destroyBehavior string) error {
+	switch destroyBehavior {
+	case "detach":
+		return detachSRResource(session, ref)
+	case "destroy":
+		err := detachSRResource(session, ref)
+		if err != nil {
+			return err
+		}
+		err = xenapi.SR.Destroy(session, ref)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		return nil
+	default:
+		return cleanupSRResource(session, ref)
+	}
+}
+
+// createSRSecret replaces the password in the device config with the UUID of a new secret
+func createSRSecret(session *xenapi.Session, deviceConfig map[string]string) (xenapi.SecretRef, error) {
 	var secretRef xenapi.SecretRef
 	keys := []string{"cifspassword", "password", "chappassword"}
-	if params.DeviceConfig != nil {
-		for _, key := range keys {
-			value, exists := params.DeviceConfig[key]
-			if exists {
-				delete(params.DeviceConfig, key)
-				secretRecord := xenapi.SecretRecord{Value: value}
-				secretRef, err := xenapi.Secret.Create(session, secretRecord)
-				if err != nil {
-					return srRef, errors.New(err.Error())
-				}
-				secretUUID, err := xenapi.Secret.GetUUID(session, secretRef)
-				if err != nil {
-					return srRef, errors.New(err.Error())
-				}
-				params.DeviceConfig[key+"_secret"] = secretUUID
-				break
-			}
+	for _, key := range keys {
+		value, exists := deviceConfig[key]
+		if !exists {
+			continue
 		}
+		delete(deviceConfig, key)
+		secretRecord := xenapi.SecretRecord{Value: value}
+		secretRef, err := xenapi.Secret.Create(session, secretRecord)
+		if err != nil {
+			return secretRef, errors.New(err.Error())
+		}
+		secretUUID, err := xenapi.Secret.GetUUID(session, secretRef)
+		if err != nil {
+			return secretRef, errors.New(err.Error())
+		}
+		deviceConfig[key+"_secret"] = secretUUID
+		return secretRef, nil
+	}
+	return secretRef, nil
+}
+
+func createSRResource(session *xenapi.Session, params srCreateParams) (xenapi.SRRef, error) {
+	var srRef xenapi.SRRef
+	// Create secret for password
+	secretRef, err := createSRSecret(session, params.DeviceConfig)
+	if err != nil {
+		return srRef, err
 	}
 	// Create SR
-	srRef, err := xenapi.SR.Create(session, params.Host, params.DeviceConfig, params.PhysicalSize, params.NameLabel, params.NameDescription, params.TypeKey, params.ContentType, params.Shared, params.SmConfig)
+	srRef, err = xenapi.SR.Create(session, params.Host, params.DeviceConfig, params.PhysicalSize, params.NameLabel, params.NameDescription, params.TypeKey, params.ContentType, params.Shared, params.SmConfig)
 	if err != nil {
 		errDestroy := xenapi.Secret.Destroy(session, secretRef)
 		if errDestroy != nil {
@@ -365,21 +483,423 @@ func createSRResource(session *xenapi.Session, params srCreateParams) (xenapi.SR
 			}
 		}
 	}
+	err = setSRAutoScan(session, srRef, params.ContentType)
+	if err != nil {
+		return srRef, err
+	}
+	return srRef, nil
+}
+
+func setSRAutoScan(session *xenapi.Session, srRef xenapi.SRRef, contentType string) error {
 	otherConfig, err := xenapi.SR.GetOtherConfig(session, srRef)
 	if err != nil {
-		return srRef, errors.New(err.Error())
+		return errors.New(err.Error())
 	}
 	otherConfig["auto-scan"] = "false"
-	if params.ContentType == "iso" {
+	if contentType == "iso" {
 		otherConfig["auto-scan"] = "true"
 	}
 	err = xenapi.SR.SetOtherConfig(session, srRef, otherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// srProbeSRList is the XML result of SR.probe when the device config locates the existing SRs
+type srProbeSRList struct {
+	SRs []struct {
+		UUID string `xml:"UUID"`
+	} `xml:"SR"`
+}
+
+func parseSRProbeUUIDs(result string) ([]string, error) {
+	var uuids []string
+	var srList srProbeSRList
+	err := xml.Unmarshal([]byte(result), &srList)
+	if err != nil {
+		return uuids, errors.New("unable to parse SR probe result. " + err.Error())
+	}
+	for _, sr := range srList.SRs {
+		uuids = append(uuids, strings.TrimSpace(sr.UUID))
+	}
+	return uuids, nil
+}
+
+// probeSRUUID runs SR.probe to find the UUID of the existing SR on the storage
+func probeSRUUID(session *xenapi.Session, params srCreateParams) (string, error) {
+	result, err := xenapi.SR.Probe(session, params.Host, params.DeviceConfig, params.TypeKey, params.SmConfig)
+	if err != nil {
+		return "", errors.New(err.Error())
+	}
+	uuids, err := parseSRProbeUUIDs(result)
+	if err != nil {
+		return "", err
+	}
+	if len(uuids) == 0 {
+		return "", errors.New("unable to find the existing SR on the storage, use mode \"create\" to create a new one")
+	}
+	if len(uuids) > 1 {
+		return "", fmt.Errorf("found multiple SRs %s on the storage, unable to decide which one to attach", strings.Join(uuids, ", "))
+	}
+	return uuids[0], nil
+}
+
+// plugSRPBDs creates the PBDs of the SR on the hosts which don't have one, and plugs them with the coordinator first
+func plugSRPBDs(session *xenapi.Session, srRef xenapi.SRRef, params srCreateParams) error {
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return err
+	}
+	hostRefs := []xenapi.HostRef{params.Host}
+	if params.Shared {
+		hostRefs = []xenapi.HostRef{coordinatorRef}
+		allHostRefs, err := xenapi.Host.GetAll(session)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		for _, hostRef := range allHostRefs {
+			if hostRef != coordinatorRef {
+				hostRefs = append(hostRefs, hostRef)
+			}
+		}
+	}
+	pbdRefs, err := xenapi.SR.GetPBDs(session, srRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	pbdsByHost := make(map[xenapi.HostRef]xenapi.PBDRecord)
+	for _, pbdRef := range pbdRefs {
+		pbdRecord, err := xenapi.PBD.GetRecord(session, pbdRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		pbdsByHost[pbdRecord.Host] = pbdRecord
+	}
+	for _, hostRef := range hostRefs {
+		pbdRecord, ok := pbdsByHost[hostRef]
+		if ok && pbdRecord.CurrentlyAttached {
+			continue
+		}
+		var pbdRef xenapi.PBDRef
+		if ok {
+			pbdRef, err = xenapi.PBD.GetByUUID(session, pbdRecord.UUID)
+		} else {
+			pbdRef, err = xenapi.PBD.Create(session, xenapi.PBDRecord{
+				Host:         hostRef,
+				SR:           srRef,
+				DeviceConfig: params.DeviceConfig,
+			})
+		}
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		err = xenapi.PBD.Plug(session, pbdRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// repairSRPBDs creates the PBDs of the shared SR for the new pool members, and re-plugs the detached PBDs
+func repairSRPBDs(session *xenapi.Session, srRef xenapi.SRRef) error {
+	srRecord, err := xenapi.SR.GetRecord(session, srRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if len(srRecord.PBDs) == 0 {
+		return fmt.Errorf("unable to find any PBD of SR %s", srRecord.UUID)
+	}
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return err
+	}
+	// The device config of the new PBDs is copied from the coordinator if possible
+	var params srCreateParams
+	for i, pbdRef := range srRecord.PBDs {
+		pbdRecord, err := xenapi.PBD.GetRecord(session, pbdRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if i == 0 || pbdRecord.Host == coordinatorRef {
+			params.Host = pbdRecord.Host
+			params.DeviceConfig = pbdRecord.DeviceConfig
+		}
+	}
+	params.Shared = srRecord.Shared
+	return plugSRPBDs(session, srRef, params)
+}
+
+type srPBDModel struct {
+	UUID              types.String `tfsdk:"uuid"`
+	CurrentlyAttached types.Bool   `tfsdk:"currently_attached"`
+}
+
+var srPBDModelAttrTypes = map[string]attr.Type{
+	"uuid":               types.StringType,
+	"currently_attached": types.BoolType,
+}
+
+func srPBDsSchema() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		MarkdownDescription: "The PBD of the storage repository on each host, the key is the host UUID." + "<br />" +
+			"When the resource is refreshed, an update is planned if any PBD is detached or any new pool member has no PBD of the shared storage repository. The detached PBDs are plugged again, and the PBDs of the new pool members are created, when the resource is updated.",
+		Computed: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"uuid": schema.StringAttribute{
+					MarkdownDescription: "The UUID of the PBD.",
+					Computed:            true,
+				},
+				"currently_attached": schema.BoolAttribute{
+					MarkdownDescription: "True if the storage repository is attached on the host.",
+					Computed:            true,
+				},
+			},
+		},
+	}
+}
+
+func getSRPBDsValue(ctx context.Context, session *xenapi.Session, pbdRefs []xenapi.PBDRef) (types.Map, error) {
+	pbds := make(map[string]srPBDModel)
+	for _, pbdRef := range pbdRefs {
+		pbdRecord, err := xenapi.PBD.GetRecord(session, pbdRef)
+		if err != nil {
+			return types.MapNull(types.ObjectType{AttrTypes: srPBDModelAttrTypes}), errors.New(err.Error())
+		}
+		hostUUID, err := getUUIDFromHostRef(session, pbdRecord.Host)
+		if err != nil {
+			return types.MapNull(types.ObjectType{AttrTypes: srPBDModelAttrTypes}), err
+		}
+		pbds[hostUUID] = srPBDModel{
+			UUID:              types.StringValue(pbdRecord.UUID),
+			CurrentlyAttached: types.BoolValue(pbdRecord.CurrentlyAttached),
+		}
+	}
+	pbdsValue, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: srPBDModelAttrTypes}, pbds)
+	if diags.HasError() {
+		return pbdsValue, errors.New("unable to update data for SR pbds")
+	}
+	return pbdsValue, nil
+}
+
+// isSRPBDsChanged returns true if any PBD in pbds is detached, or any pool member has no PBD of the shared SR
+func isSRPBDsChanged(ctx context.Context, session *xenapi.Session, srUUID string, pbds types.Map) (bool, error) {
+	pbdsMap := make(map[string]srPBDModel)
+	diags := pbds.ElementsAs(ctx, &pbdsMap, false)
+	if diags.HasError() {
+		return false, errors.New("unable to access SR pbds")
+	}
+	for _, pbd := range pbdsMap {
+		if !pbd.CurrentlyAttached.ValueBool() {
+			return true, nil
+		}
+	}
+	srRef, err := xenapi.SR.GetByUUID(session, srUUID)
+	if err != nil {
+		return false, errors.New(err.Error())
+	}
+	shared, err := xenapi.SR.GetShared(session, srRef)
+	if err != nil {
+		return false, errors.New(err.Error())
+	}
+	if !shared {
+		return false, nil
+	}
+	hostRecords, err := xenapi.Host.GetAllRecords(session)
+	if err != nil {
+		return false, errors.New(err.Error())
+	}
+	for _, hostRecord := range hostRecords {
+		if _, ok := pbdsMap[hostRecord.UUID]; !ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// modifySRPBDsPlan plans an update to plug the detached PBDs and create the PBDs for the new pool members,
+// the PBDs are repaired by the update, so refreshing and planning the SR resources don't change them
+func modifySRPBDsPlan(ctx context.Context, session *xenapi.Session, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || session == nil {
+		return
+	}
+	var srUUID types.String
+	var pbds types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("uuid"), &srUUID)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("pbds"), &pbds)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	changed, err := isSRPBDsChanged(ctx, session, srUUID.ValueString(), pbds)
+	if err != nil || !changed {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pbds"), types.MapUnknown(types.ObjectType{AttrTypes: srPBDModelAttrTypes}))...)
+}
+
+// attachSRResource reattaches the existing SR on the storage to the pool without formatting it,
+// the SR is introduced if the pool doesn't know it, or reused if it's known but detached.
+func attachSRResource(session *xenapi.Session, params srCreateParams) (xenapi.SRRef, error) {
+	var srRef xenapi.SRRef
+	srUUID, err := probeSRUUID(session, params)
+	if err != nil {
+		return srRef, err
+	}
+	srRecords, err := xenapi.SR.GetAllRecords(session)
 	if err != nil {
 		return srRef, errors.New(err.Error())
 	}
+	introduced := true
+	for ref, srRecord := range srRecords {
+		if srRecord.UUID != srUUID {
+			continue
+		}
+		for _, pbdRef := range srRecord.PBDs {
+			currentlyAttached, err := xenapi.PBD.GetCurrentlyAttached(session, pbdRef)
+			if err != nil {
+				return srRef, errors.New(err.Error())
+			}
+			if currentlyAttached {
+				return srRef, fmt.Errorf("the SR %s is already attached to the pool, import it instead", srUUID)
+			}
+		}
+		srRef = ref
+		introduced = false
+		break
+	}
+	secretRef, err := createSRSecret(session, params.DeviceConfig)
+	if err != nil {
+		return srRef, err
+	}
+	if introduced {
+		srRef, err = xenapi.SR.Introduce(session, srUUID, params.NameLabel, params.NameDescription, params.TypeKey, params.ContentType, params.Shared, params.SmConfig)
+		if err != nil {
+			return srRef, destroySRSecretOnError(session, secretRef, errors.New(err.Error()))
+		}
+	} else {
+		err = xenapi.SR.SetNameLabel(session, srRef, params.NameLabel)
+		if err != nil {
+			return srRef, destroySRSecretOnError(session, secretRef, errors.New(err.Error()))
+		}
+		err = xenapi.SR.SetNameDescription(session, srRef, params.NameDescription)
+		if err != nil {
+			return srRef, destroySRSecretOnError(session, secretRef, errors.New(err.Error()))
+		}
+	}
+	err = plugSRPBDs(session, srRef, params)
+	if err == nil {
+		err = setSRAutoScan(session, srRef, params.ContentType)
+	}
+	if err != nil {
+		errCleanup := detachSRResource(session, srRef)
+		if errCleanup == nil && introduced {
+			errCleanup = xenapi.SR.Forget(session, srRef)
+		}
+		if errCleanup != nil {
+			err = errors.New(err.Error() + "\n" + errCleanup.Error())
+		}
+		return srRef, destroySRSecretOnError(session, secretRef, err)
+	}
 	return srRef, nil
 }
 
+// destroySRSecretOnError destroys the secret created for the failed SR, and returns the error with the cleanup error appended
+func destroySRSecretOnError(session *xenapi.Session, secretRef xenapi.SecretRef, err error) error {
+	if secretRef == "" {
+		return err
+	}
+	errDestroy := xenapi.Secret.Destroy(session, secretRef)
+	if errDestroy != nil {
+		return errors.New(err.Error() + "\n" + errDestroy.Error())
+	}
+	return err
+}
+
+// createOrAttachSRResource creates a new SR, or attaches the existing SR on the storage when mode is "attach".
+func createOrAttachSRResource(session *xenapi.Session, params srCreateParams, mode string) (xenapi.SRRef, error) {
+	if mode == "attach" {
+		return attachSRResource(session, params)
+	}
+	return createSRResource(session, params)
+}
+
+func srModeSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "The way to add the storage repository to the pool, default to be `\"create\"`, for example, `\"create\"`, `\"attach\"`. Used when creating the SR." + "<br />" +
+			"`\"create\"` creates a new storage repository on the storage. `\"attach\"` finds the existing storage repository on the storage by probing, and reattaches it to the pool with the data kept. `\"attach\"` is not supported by the ISO library.",
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString("create"),
+		Validators: []validator.String{
+			stringvalidator.OneOf("create", "attach"),
+			srModeValidator{},
+		},
+	}
+}
+
+// srModeValidator checks mode "attach" isn't used with the ISO library, which isn't formatted by creation
+type srModeValidator struct{}
+
+func (v srModeValidator) Description(_ context.Context) string {
+	return "value must not be \"attach\" when type or content_type is \"iso\""
+}
+
+func (v srModeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v srModeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.ConfigValue.ValueString() != "attach" {
+		return
+	}
+	attributes := req.Config.Schema.GetAttributes()
+	for _, name := range []string{"type", "content_type"} {
+		if _, ok := attributes[name]; !ok {
+			continue
+		}
+		var value types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if value.ValueString() == "iso" {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Invalid SR mode",
+				"mode \"attach\" is not supported by the ISO library, set mode to \"create\" instead.",
+			)
+			return
+		}
+	}
+}
+
+func srDestroyBehaviorSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "The behavior when the resource is destroyed, default to be `\"forget\"`, for example, `\"forget\"`, `\"detach\"`, `\"destroy\"`." + "<br />" +
+			"`\"forget\"` detaches the storage repository and removes it from the pool, the data is kept on the storage. `\"detach\"` only detaches the storage repository from the hosts. `\"destroy\"` detaches the storage repository and wipes it from the storage." +
+			"\n\n!> **Warning:** All the data of the storage repository will be lost when it's destroyed with `\"destroy\"`.",
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString("forget"),
+		Validators: []validator.String{
+			stringvalidator.OneOf("forget", "detach", "destroy"),
+		},
+	}
+}
+
+// updateSRModeAndDestroyBehavior sets the default values of mode and destroy_behavior which are not stored in the pool, for example, after import
+func updateSRModeAndDestroyBehavior(mode *types.String, destroyBehavior *types.String) {
+	if mode.IsNull() {
+		*mode = types.StringValue("create")
+	}
+	if destroyBehavior.IsNull() {
+		*destroyBehavior = types.StringValue("forget")
+	}
+}
+
 type nfsResourceModel struct {
 	NameLabel       types.String `tfsdk:"name_label"`
 	NameDescription types.String `tfsdk:"name_description"`
@@ -387,6 +907,9 @@ type nfsResourceModel struct {
 	StorageLocation types.String `tfsdk:"storage_location"`
 	Version         types.String `tfsdk:"version"`
 	AdvancedOptions types.String `tfsdk:"advanced_options"`
+	Mode            types.String `tfsdk:"mode"`
+	DestroyBehavior types.String `tfsdk:"destroy_behavior"`
+	PBDs            types.Map    `tfsdk:"pbds"`
 	UUID            types.String `tfsdk:"uuid"`
 	ID              types.String `tfsdk:"id"`
 }
@@ -420,8 +943,9 @@ func getNFSCreateParams(session *xenapi.Session, data nfsResourceModel) (srCreat
 	return params, nil
 }
 
-func updateNFSResourceModel(srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *nfsResourceModel) error {
+func updateNFSResourceModel(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *nfsResourceModel) error {
 	data.NameLabel = types.StringValue(srRecord.NameLabel)
+	updateSRModeAndDestroyBehavior(&data.Mode, &data.DestroyBehavior)
 	if srRecord.Type == "iso" {
 		location, ok := pbdRecord.DeviceConfig["location"]
 		if !ok {
@@ -444,12 +968,12 @@ func updateNFSResourceModel(srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord
 		return errors.New(`unable to find "nfsversion" in PBD device config`)
 	}
 	data.Version = types.StringValue(nfsVersion)
-	err := updateNFSResourceModelComputed(srRecord, pbdRecord, data)
+	err := updateNFSResourceModelComputed(ctx, session, srRecord, pbdRecord, data)
 
 	return err
 }
 
-func updateNFSResourceModelComputed(srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *nfsResourceModel) error {
+func updateNFSResourceModelComputed(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *nfsResourceModel) error {
 	data.UUID = types.StringValue(srRecord.UUID)
 	data.ID = types.StringValue(srRecord.UUID)
 	data.NameDescription = types.StringValue(srRecord.NameDescription)
@@ -460,6 +984,12 @@ func updateNFSResourceModelComputed(srRecord xenapi.SRRecord, pbdRecord xenapi.P
 	}
 	data.AdvancedOptions = types.StringValue(advancedOptions)
 
+	pbds, err := getSRPBDsValue(ctx, session, srRecord.PBDs)
+	if err != nil {
+		return err
+	}
+	data.PBDs = pbds
+
 	return nil
 }
 
@@ -499,6 +1029,9 @@ type smbResourceModel struct {
 	StorageLocation types.String `tfsdk:"storage_location"`
 	Username        types.String `tfsdk:"username"`
 	Password        types.String `tfsdk:"password"`
+	Mode            types.String `tfsdk:"mode"`
+	DestroyBehavior types.String `tfsdk:"destroy_behavior"`
+	PBDs            types.Map    `tfsdk:"pbds"`
 	UUID            types.String `tfsdk:"uuid"`
 	ID              types.String `tfsdk:"id"`
 }
@@ -551,8 +1084,9 @@ func getSMBCreateParams(session *xenapi.Session, data smbResourceModel) (srCreat
 	return params, nil
 }
 
-func updateSMBResourceModel(srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *smbResourceModel) error {
+func updateSMBResourceModel(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *smbResourceModel) error {
 	data.NameLabel = types.StringValue(srRecord.NameLabel)
+	updateSRModeAndDestroyBehavior(&data.Mode, &data.DestroyBehavior)
 	if srRecord.Type == "iso" {
 		location, ok := pbdRecord.DeviceConfig["location"]
 		if !ok {
@@ -575,17 +1109,23 @@ func updateSMBResourceModel(srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord
 			data.StorageLocation = types.StringValue(server + ":" + serverPath)
 		}
 	}
-	err := updateSMBResourceModelComputed(srRecord, data)
+	err := updateSMBResourceModelComputed(ctx, session, srRecord, data)
 
 	return err
 }
 
-func updateSMBResourceModelComputed(srRecord xenapi.SRRecord, data *smbResourceModel) error {
+func updateSMBResourceModelComputed(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, data *smbResourceModel) error {
 	data.UUID = types.StringValue(srRecord.UUID)
 	data.ID = types.StringValue(srRecord.UUID)
 	data.NameDescription = types.StringValue(srRecord.NameDescription)
 	data.Type = types.StringValue(srRecord.Type)
 
+	pbds, err := getSRPBDsValue(ctx, session, srRecord.PBDs)
+	if err != nil {
+		return err
+	}
+	data.PBDs = pbds
+
 	return nil
 }
 
@@ -611,3 +1151,447 @@ func smbResourceModelUpdate(session *xenapi.Session, ref xenapi.SRRef, data smbR
 
 	return nil
 }
+
+const iscsiDefaultPort = 3260
+
+const gfs2SRType = "gfs2"
+
+type iscsiResourceModel struct {
+	NameLabel       types.String `tfsdk:"name_label"`
+	NameDescription types.String `tfsdk:"name_description"`
+	Type            types.String `tfsdk:"type"`
+	Target          types.String `tfsdk:"target"`
+	Port            types.Int64  `tfsdk:"port"`
+	TargetIQN       types.String `tfsdk:"target_iqn"`
+	SCSIid          types.String `tfsdk:"scsi_id"`
+	ChapUsername    types.String `tfsdk:"chap_username"`
+	ChapPassword    types.String `tfsdk:"chap_password"`
+	Multipath       types.Bool   `tfsdk:"multipath"`
+	Mode            types.String `tfsdk:"mode"`
+	DestroyBehavior types.String `tfsdk:"destroy_behavior"`
+	PBDs            types.Map    `tfsdk:"pbds"`
+	UUID            types.String `tfsdk:"uuid"`
+	ID              types.String `tfsdk:"id"`
+}
+
+// getISCSIDeviceConfig returns the device config to probe or create the iSCSI SR, targetIQN and SCSIid are skipped when they are empty
+func getISCSIDeviceConfig(target string, port int64, targetIQN string, scsiID string, chapUsername string, chapPassword string) map[string]string {
+	deviceConfig := make(map[string]string)
+	deviceConfig["target"] = strings.TrimSpace(target)
+	deviceConfig["port"] = strconv.FormatInt(port, 10)
+	if targetIQN != "" {
+		deviceConfig["targetIQN"] = targetIQN
+	}
+	if scsiID != "" {
+		deviceConfig["SCSIid"] = scsiID
+	}
+	if chapUsername != "" {
+		deviceConfig["chapuser"] = chapUsername
+		deviceConfig["chappassword"] = chapPassword
+	}
+	return deviceConfig
+}
+
+// probeISCSI runs SR.probe_ext on the coordinator to discover the IQNs of the target, or the LUNs of the IQN when targetIQN is in the device config
+func probeISCSI(session *xenapi.Session, deviceConfig map[string]string) ([]xenapi.ProbeResultRecord, error) {
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return nil, err
+	}
+	results, err := xenapi.SR.ProbeExt(session, coordinatorRef, deviceConfig, "lvmoiscsi", map[string]string{})
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	return results, nil
+}
+
+// checkMultipathEnabled returns error if multipathing isn't enabled on any host of the pool
+func checkMultipathEnabled(session *xenapi.Session) error {
+	hostRecords, err := xenapi.Host.GetAllRecords(session)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	for _, hostRecord := range hostRecords {
+		// other_config "multipathing" is kept by the older hosts
+		if !hostRecord.Multipathing && hostRecord.OtherConfig["multipathing"] != "true" {
+			return fmt.Errorf("multipathing isn't enabled on host %s, enable it in maintenance mode before creating the SR", hostRecord.NameLabel)
+		}
+	}
+	return nil
+}
+
+func getISCSICreateParams(session *xenapi.Session, data iscsiResourceModel) (srCreateParams, error) {
+	var params srCreateParams
+	if data.Multipath.ValueBool() {
+		err := checkMultipathEnabled(session)
+		if err != nil {
+			return params, err
+		}
+	}
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return params, err
+	}
+	params.Host = coordinatorRef
+	params.TypeKey = data.Type.ValueString()
+	params.DeviceConfig = getISCSIDeviceConfig(
+		data.Target.ValueString(),
+		data.Port.ValueInt64(),
+		data.TargetIQN.ValueString(),
+		data.SCSIid.ValueString(),
+		data.ChapUsername.ValueString(),
+		data.ChapPassword.ValueString(),
+	)
+	if params.TypeKey == gfs2SRType {
+		err = checkClusterReady(session)
+		if err != nil {
+			return params, err
+		}
+		params.DeviceConfig["provider"] = "iscsi"
+	}
+	params.NameLabel = data.NameLabel.ValueString()
+	params.NameDescription = data.NameDescription.ValueString()
+	params.Shared = true
+	params.SmConfig = make(map[string]string)
+	if data.Multipath.ValueBool() {
+		params.SmConfig["multipathable"] = "true"
+	}
+
+	return params, nil
+}
+
+func updateISCSIResourceModel(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *iscsiResourceModel) error {
+	data.NameLabel = types.StringValue(srRecord.NameLabel)
+	updateSRModeAndDestroyBehavior(&data.Mode, &data.DestroyBehavior)
+	data.Type = types.StringValue(srRecord.Type)
+	target, ok := pbdRecord.DeviceConfig["target"]
+	if !ok {
+		return errors.New(`unable to find "target" in PBD device config`)
+	}
+	data.Target = types.StringValue(target)
+	targetIQN, ok := pbdRecord.DeviceConfig["targetIQN"]
+	if !ok {
+		return errors.New(`unable to find "targetIQN" in PBD device config`)
+	}
+	data.TargetIQN = types.StringValue(targetIQN)
+	scsiID, ok := pbdRecord.DeviceConfig["SCSIid"]
+	if !ok {
+		return errors.New(`unable to find "SCSIid" in PBD device config`)
+	}
+	data.SCSIid = types.StringValue(scsiID)
+	data.Port = types.Int64Value(iscsiDefaultPort)
+	port, ok := pbdRecord.DeviceConfig["port"]
+	if ok && port != "" {
+		value, err := strconv.ParseInt(port, 10, 64)
+		if err != nil {
+			return errors.New("unable to parse iSCSI port " + port)
+		}
+		data.Port = types.Int64Value(value)
+	}
+	chapUsername, ok := pbdRecord.DeviceConfig["chapuser"]
+	if ok && chapUsername != "" {
+		data.ChapUsername = types.StringValue(chapUsername)
+	}
+	data.Multipath = types.BoolValue(srRecord.SmConfig["multipathable"] == "true")
+
+	return updateISCSIResourceModelComputed(ctx, session, srRecord, data)
+}
+
+func updateISCSIResourceModelComputed(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, data *iscsiResourceModel) error {
+	data.UUID = types.StringValue(srRecord.UUID)
+	data.ID = types.StringValue(srRecord.UUID)
+	data.NameDescription = types.StringValue(srRecord.NameDescription)
+
+	pbds, err := getSRPBDsValue(ctx, session, srRecord.PBDs)
+	if err != nil {
+		return err
+	}
+	data.PBDs = pbds
+
+	return nil
+}
+
+func iscsiResourceModelUpdateCheck(data iscsiResourceModel, dataState iscsiResourceModel) error {
+	if data.Type != dataState.Type {
+		return errors.New(`"type" doesn't expected to be updated`)
+	}
+	if strings.TrimSpace(data.Target.ValueString()) != strings.TrimSpace(dataState.Target.ValueString()) {
+		return errors.New(`"target" doesn't expected to be updated`)
+	}
+	if data.Port != dataState.Port {
+		return errors.New(`"port" doesn't expected to be updated`)
+	}
+	if data.TargetIQN != dataState.TargetIQN {
+		return errors.New(`"target_iqn" doesn't expected to be updated`)
+	}
+	if data.SCSIid != dataState.SCSIid {
+		return errors.New(`"scsi_id" doesn't expected to be updated`)
+	}
+	if data.Multipath != dataState.Multipath {
+		return errors.New(`"multipath" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+func iscsiResourceModelUpdate(session *xenapi.Session, ref xenapi.SRRef, data iscsiResourceModel) error {
+	err := xenapi.SR.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.SR.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	return nil
+}
+
+type iscsiProbeDataSourceModel struct {
+	Target       types.String           `tfsdk:"target"`
+	Port         types.Int64            `tfsdk:"port"`
+	TargetIQN    types.String           `tfsdk:"target_iqn"`
+	ChapUsername types.String           `tfsdk:"chap_username"`
+	ChapPassword types.String           `tfsdk:"chap_password"`
+	IQNs         types.List             `tfsdk:"iqns"`
+	SCSIids      types.List             `tfsdk:"scsi_ids"`
+	DataItems    []iscsiProbeResultData `tfsdk:"data_items"`
+}
+
+type iscsiProbeResultData struct {
+	Configuration types.Map  `tfsdk:"configuration"`
+	ExtraInfo     types.Map  `tfsdk:"extra_info"`
+	Complete      types.Bool `tfsdk:"complete"`
+}
+
+func updateISCSIProbeDataSourceModel(ctx context.Context, results []xenapi.ProbeResultRecord, data *iscsiProbeDataSourceModel) error {
+	iqns := []string{}
+	scsiIDs := []string{}
+	data.DataItems = []iscsiProbeResultData{}
+	for _, result := range results {
+		var item iscsiProbeResultData
+		var diags diag.Diagnostics
+		item.Configuration, diags = types.MapValueFrom(ctx, types.StringType, result.Configuration)
+		if diags.HasError() {
+			return errors.New("unable to read probe result configuration")
+		}
+		item.ExtraInfo, diags = types.MapValueFrom(ctx, types.StringType, result.ExtraInfo)
+		if diags.HasError() {
+			return errors.New("unable to read probe result extra info")
+		}
+		item.Complete = types.BoolValue(result.Complete)
+		data.DataItems = append(data.DataItems, item)
+
+		if iqn, ok := result.Configuration["targetIQN"]; ok && !slices.Contains(iqns, iqn) {
+			iqns = append(iqns, iqn)
+		}
+		if scsiID, ok := result.Configuration["SCSIid"]; ok && !slices.Contains(scsiIDs, scsiID) {
+			scsiIDs = append(scsiIDs, scsiID)
+		}
+	}
+	slices.Sort(iqns)
+	slices.Sort(scsiIDs)
+	var diags diag.Diagnostics
+	data.IQNs, diags = types.ListValueFrom(ctx, types.StringType, iqns)
+	if diags.HasError() {
+		return errors.New("unable to read probe result IQNs")
+	}
+	data.SCSIids, diags = types.ListValueFrom(ctx, types.StringType, scsiIDs)
+	if diags.HasError() {
+		return errors.New("unable to read probe result SCSI IDs")
+	}
+	return nil
+}
+
+type hbaResourceModel struct {
+	NameLabel       types.String `tfsdk:"name_label"`
+	NameDescription types.String `tfsdk:"name_description"`
+	Type            types.String `tfsdk:"type"`
+	SCSIid          types.String `tfsdk:"scsi_id"`
+	Force           types.Bool   `tfsdk:"force"`
+	Mode            types.String `tfsdk:"mode"`
+	DestroyBehavior types.String `tfsdk:"destroy_behavior"`
+	PBDs            types.Map    `tfsdk:"pbds"`
+	UUID            types.String `tfsdk:"uuid"`
+	ID              types.String `tfsdk:"id"`
+}
+
+// hbaProbeDevlist is the XML result of SR.probe for lvmohba without SCSIid
+type hbaProbeDevlist struct {
+	BlockDevices []hbaProbeBlockDevice `xml:"BlockDevice"`
+}
+
+type hbaProbeBlockDevice struct {
+	Path    string `xml:"path"`
+	SCSIid  string `xml:"SCSIid"`
+	Vendor  string `xml:"vendor"`
+	Serial  string `xml:"serial"`
+	Size    string `xml:"size"`
+	Adapter string `xml:"adapter"`
+	LUN     string `xml:"lun"`
+}
+
+// hbaProbeDevlistErrorCode is the error of SR.probe for lvmohba without SCSIid, the devlist XML is in the error description
+const hbaProbeDevlistErrorCode = "SR_BACKEND_FAILURE_107"
+
+// probeHBADevices runs SR.probe on the host to list the LUNs visible through the HBAs
+func probeHBADevices(session *xenapi.Session, hostRef xenapi.HostRef) ([]hbaProbeBlockDevice, error) {
+	result, err := xenapi.SR.Probe(session, hostRef, map[string]string{}, "lvmohba", map[string]string{})
+	if err != nil {
+		if !strings.Contains(err.Error(), hbaProbeDevlistErrorCode) {
+			return nil, errors.New(err.Error())
+		}
+		result = err.Error()
+	}
+	return parseHBAProbeDevlist(result)
+}
+
+// parseHBAProbeDevlist parses the <Devlist> XML in the SR.probe result or error description
+func parseHBAProbeDevlist(result string) ([]hbaProbeBlockDevice, error) {
+	start := strings.Index(result, "<Devlist")
+	end := strings.LastIndex(result, "</Devlist>")
+	if start < 0 || end < start {
+		return nil, errors.New("unable to find the devlist in HBA probe result: " + result)
+	}
+	devlistXML := result[start : end+len("</Devlist>")]
+	// the error description may be escaped when formatted in the error message
+	devlistXML = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`).Replace(devlistXML)
+	var devlist hbaProbeDevlist
+	err := xml.Unmarshal([]byte(devlistXML), &devlist)
+	if err != nil {
+		return nil, errors.New("unable to parse HBA probe result. " + err.Error())
+	}
+	return devlist.BlockDevices, nil
+}
+
+// getSRUUIDsOnLUN returns the UUIDs of the SRs which already exist on the LUN
+func getSRUUIDsOnLUN(session *xenapi.Session, hostRef xenapi.HostRef, scsiID string) ([]string, error) {
+	var uuids []string
+	result, err := xenapi.SR.Probe(session, hostRef, map[string]string{"SCSIid": scsiID}, "lvmohba", map[string]string{})
+	if err != nil {
+		return uuids, errors.New(err.Error())
+	}
+	return parseSRProbeUUIDs(result)
+}
+
+func getHBACreateParams(session *xenapi.Session, data hbaResourceModel) (srCreateParams, error) {
+	var params srCreateParams
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return params, err
+	}
+	if !data.Force.ValueBool() && data.Mode.ValueString() != "attach" {
+		srUUIDs, err := getSRUUIDsOnLUN(session, coordinatorRef, data.SCSIid.ValueString())
+		if err != nil {
+			return params, err
+		}
+		if len(srUUIDs) > 0 {
+			return params, fmt.Errorf("the LUN %s already holds the SR %s, set \"force\" to true to format it", data.SCSIid.ValueString(), strings.Join(srUUIDs, ", "))
+		}
+	}
+	params.Host = coordinatorRef
+	params.TypeKey = data.Type.ValueString()
+	params.DeviceConfig = map[string]string{"SCSIid": data.SCSIid.ValueString()}
+	if params.TypeKey == gfs2SRType {
+		err = checkClusterReady(session)
+		if err != nil {
+			return params, err
+		}
+		params.DeviceConfig["provider"] = "hba"
+	}
+	params.NameLabel = data.NameLabel.ValueString()
+	params.NameDescription = data.NameDescription.ValueString()
+	params.Shared = true
+	params.SmConfig = make(map[string]string)
+
+	return params, nil
+}
+
+func updateHBAResourceModel(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, pbdRecord xenapi.PBDRecord, data *hbaResourceModel) error {
+	data.NameLabel = types.StringValue(srRecord.NameLabel)
+	updateSRModeAndDestroyBehavior(&data.Mode, &data.DestroyBehavior)
+	data.Type = types.StringValue(srRecord.Type)
+	scsiID, ok := pbdRecord.DeviceConfig["SCSIid"]
+	if !ok {
+		return errors.New(`unable to find "SCSIid" in PBD device config`)
+	}
+	data.SCSIid = types.StringValue(scsiID)
+	if data.Force.IsNull() {
+		data.Force = types.BoolValue(false)
+	}
+
+	return updateHBAResourceModelComputed(ctx, session, srRecord, data)
+}
+
+func updateHBAResourceModelComputed(ctx context.Context, session *xenapi.Session, srRecord xenapi.SRRecord, data *hbaResourceModel) error {
+	data.UUID = types.StringValue(srRecord.UUID)
+	data.ID = types.StringValue(srRecord.UUID)
+	data.NameDescription = types.StringValue(srRecord.NameDescription)
+
+	pbds, err := getSRPBDsValue(ctx, session, srRecord.PBDs)
+	if err != nil {
+		return err
+	}
+	data.PBDs = pbds
+
+	return nil
+}
+
+func hbaResourceModelUpdateCheck(data hbaResourceModel, dataState hbaResourceModel) error {
+	if data.Type != dataState.Type {
+		return errors.New(`"type" doesn't expected to be updated`)
+	}
+	if data.SCSIid != dataState.SCSIid {
+		return errors.New(`"scsi_id" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+func hbaResourceModelUpdate(session *xenapi.Session, ref xenapi.SRRef, data hbaResourceModel) error {
+	err := xenapi.SR.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.SR.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	return nil
+}
+
+type hbaLUNDataSourceModel struct {
+	Host      types.String     `tfsdk:"host"`
+	SCSIid    types.String     `tfsdk:"scsi_id"`
+	DataItems []hbaLUNItemData `tfsdk:"data_items"`
+}
+
+type hbaLUNItemData struct {
+	Host    types.String `tfsdk:"host"`
+	SCSIid  types.String `tfsdk:"scsi_id"`
+	Path    types.String `tfsdk:"path"`
+	Vendor  types.String `tfsdk:"vendor"`
+	Serial  types.String `tfsdk:"serial"`
+	Size    types.Int64  `tfsdk:"size"`
+	Adapter types.String `tfsdk:"adapter"`
+	LUN     types.String `tfsdk:"lun"`
+}
+
+func updateHBALUNItemData(hostUUID string, device hbaProbeBlockDevice, data *hbaLUNItemData) error {
+	data.Host = types.StringValue(hostUUID)
+	data.SCSIid = types.StringValue(strings.TrimSpace(device.SCSIid))
+	data.Path = types.StringValue(strings.TrimSpace(device.Path))
+	data.Vendor = types.StringValue(strings.TrimSpace(device.Vendor))
+	data.Serial = types.StringValue(strings.TrimSpace(device.Serial))
+	data.Adapter = types.StringValue(strings.TrimSpace(device.Adapter))
+	data.LUN = types.StringValue(strings.TrimSpace(device.LUN))
+	data.Size = types.Int64Value(0)
+	size := strings.TrimSpace(device.Size)
+	if size != "" {
+		value, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return errors.New("unable to parse LUN size " + size)
+		}
+		data.Size = types.Int64Value(value)
+	}
+	return nil
+}
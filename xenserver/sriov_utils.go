@@ -0,0 +1,197 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+type sriovResourceModel struct {
+	NameLabel         types.String `tfsdk:"name_label"`
+	NameDescription   types.String `tfsdk:"name_description"`
+	MTU               types.Int32  `tfsdk:"mtu"`
+	OtherConfig       types.Map    `tfsdk:"other_config"`
+	NIC               types.String `tfsdk:"nic"`
+	RequiresReboot    types.Map    `tfsdk:"requires_reboot"`
+	RemainingCapacity types.Map    `tfsdk:"remaining_capacity"`
+	UUID              types.String `tfsdk:"uuid"`
+	ID                types.String `tfsdk:"id"`
+}
+
+func getSriovNetworkBaseModel(data sriovResourceModel) networkBaseModel {
+	return networkBaseModel{
+		NameLabel:       data.NameLabel,
+		NameDescription: data.NameDescription,
+		MTU:             data.MTU,
+		Managed:         types.BoolValue(true),
+		OtherConfig:     data.OtherConfig,
+		Tags:            types.SetNull(types.StringType),
+	}
+}
+
+// createNetworkSriovs enables SR-IOV on the physical PIF of the NIC on every host
+func createNetworkSriovs(session *xenapi.Session, networkRef xenapi.NetworkRef, nic string) error {
+	pifRefs, err := getPifRefsForNIC(session, nic)
+	if err != nil {
+		return err
+	}
+	if len(pifRefs) == 0 {
+		return errors.New("unable to find PIF for NIC")
+	}
+	for _, pifRef := range pifRefs {
+		pifRecord, err := xenapi.PIF.GetRecord(session, pifRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if !slices.Contains(pifRecord.Capabilities, "sriov") {
+			hostname, err := xenapi.Host.GetHostname(session, pifRecord.Host)
+			if err != nil {
+				return errors.New(err.Error())
+			}
+			return fmt.Errorf("%s on host %s doesn't support SR-IOV", nic, hostname)
+		}
+		_, err = xenapi.NetworkSriov.Create(session, pifRef, networkRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+func getNetworkSriovRefs(session *xenapi.Session, networkRef xenapi.NetworkRef) ([]xenapi.NetworkSriovRef, error) {
+	var sriovRefs []xenapi.NetworkSriovRef
+	pifRefs, err := xenapi.Network.GetPIFs(session, networkRef)
+	if err != nil {
+		return sriovRefs, errors.New(err.Error())
+	}
+	for _, pifRef := range pifRefs {
+		sriovLogicalPIFOf, err := xenapi.PIF.GetSriovLogicalPIFOf(session, pifRef)
+		if err != nil {
+			return sriovRefs, errors.New(err.Error())
+		}
+		sriovRefs = append(sriovRefs, sriovLogicalPIFOf...)
+	}
+	return sriovRefs, nil
+}
+
+func updateSriovResourceModel(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, record xenapi.NetworkRecord, data *sriovResourceModel) error {
+	data.NameLabel = types.StringValue(record.NameLabel)
+	return updateSriovResourceModelComputed(ctx, session, ref, record, data)
+}
+
+func updateSriovResourceModelComputed(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, record xenapi.NetworkRecord, data *sriovResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	mtu, err := ToInt32(record.MTU)
+	if err != nil {
+		return err
+	}
+	data.MTU = types.Int32Value(mtu)
+	var diags diag.Diagnostics
+	data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
+	if diags.HasError() {
+		return errors.New("unable to update data for network_sriov other_config")
+	}
+
+	sriovRefs, err := getNetworkSriovRefs(session, ref)
+	if err != nil {
+		return err
+	}
+	if len(sriovRefs) == 0 {
+		return errors.New("unable to find network_sriov on network " + record.NameLabel)
+	}
+	requiresReboot := make(map[string]bool)
+	remainingCapacity := make(map[string]int64)
+	for _, sriovRef := range sriovRefs {
+		sriovRecord, err := xenapi.NetworkSriov.GetRecord(session, sriovRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		pifRecord, err := xenapi.PIF.GetRecord(session, sriovRecord.PhysicalPIF)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		hostUUID, err := xenapi.Host.GetUUID(session, pifRecord.Host)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		data.NIC = types.StringValue(getNICsNameForDevices([]string{pifRecord.Device}, "NIC")[0])
+		requiresReboot[hostUUID] = sriovRecord.RequiresReboot
+		// the remaining capacity is only available after the SR-IOV is enabled on the host
+		remainingCapacity[hostUUID] = 0
+		if !sriovRecord.RequiresReboot {
+			capacity, err := xenapi.NetworkSriov.GetRemainingCapacity(session, sriovRef)
+			if err != nil {
+				return errors.New(err.Error())
+			}
+			remainingCapacity[hostUUID] = int64(capacity)
+		}
+	}
+	data.RequiresReboot, diags = types.MapValueFrom(ctx, types.BoolType, requiresReboot)
+	if diags.HasError() {
+		return errors.New("unable to update data for network_sriov requires_reboot")
+	}
+	data.RemainingCapacity, diags = types.MapValueFrom(ctx, types.Int64Type, remainingCapacity)
+	if diags.HasError() {
+		return errors.New("unable to update data for network_sriov remaining_capacity")
+	}
+
+	return nil
+}
+
+func sriovResourceModelUpdateCheck(data sriovResourceModel, dataState sriovResourceModel) error {
+	if data.NIC != dataState.NIC {
+		return errors.New(`"nic" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+func sriovResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, data sriovResourceModel) error {
+	err := xenapi.Network.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetMTU(session, ref, int(data.MTU.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	otherConfig := make(map[string]string)
+	diags := data.OtherConfig.ElementsAs(ctx, &otherConfig, false)
+	if diags.HasError() {
+		return errors.New("unable to access network other config")
+	}
+	err = xenapi.Network.SetOtherConfig(session, ref, otherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func cleanupSriovResource(session *xenapi.Session, ref xenapi.NetworkRef) error {
+	sriovRefs, err := getNetworkSriovRefs(session, ref)
+	if err != nil {
+		return err
+	}
+	for _, sriovRef := range sriovRefs {
+		err = xenapi.NetworkSriov.Destroy(session, sriovRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	err = xenapi.Network.Destroy(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
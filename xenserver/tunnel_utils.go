@@ -0,0 +1,259 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+type tunnelResourceModel struct {
+	NameLabel       types.String `tfsdk:"name_label"`
+	NameDescription types.String `tfsdk:"name_description"`
+	MTU             types.Int32  `tfsdk:"mtu"`
+	OtherConfig     types.Map    `tfsdk:"other_config"`
+	Protocol        types.String `tfsdk:"protocol"`
+	NIC             types.String `tfsdk:"nic"`
+	Status          types.Map    `tfsdk:"status"`
+	UUID            types.String `tfsdk:"uuid"`
+	ID              types.String `tfsdk:"id"`
+}
+
+func getTunnelNetworkBaseModel(data tunnelResourceModel) networkBaseModel {
+	return networkBaseModel{
+		NameLabel:       data.NameLabel,
+		NameDescription: data.NameDescription,
+		MTU:             data.MTU,
+		Managed:         types.BoolValue(true),
+		OtherConfig:     data.OtherConfig,
+		Tags:            types.SetNull(types.StringType),
+	}
+}
+
+// getTunnelTransportPIFs returns the transport PIF on each host, the management PIF is used when nic is empty
+func getTunnelTransportPIFs(session *xenapi.Session, nic string) (map[xenapi.HostRef]xenapi.PIFRef, error) {
+	transportPIFs := make(map[xenapi.HostRef]xenapi.PIFRef)
+	if nic == "" {
+		pifRecords, err := xenapi.PIF.GetAllRecords(session)
+		if err != nil {
+			return transportPIFs, errors.New(err.Error())
+		}
+		for pifRef, pifRecord := range pifRecords {
+			if pifRecord.Management {
+				transportPIFs[pifRecord.Host] = pifRef
+			}
+		}
+	} else {
+		pifRefs, err := getPifRefsForNIC(session, nic)
+		if err != nil {
+			return transportPIFs, err
+		}
+		for _, pifRef := range pifRefs {
+			hostRef, err := xenapi.PIF.GetHost(session, pifRef)
+			if err != nil {
+				return transportPIFs, errors.New(err.Error())
+			}
+			transportPIFs[hostRef] = pifRef
+		}
+	}
+
+	hostRefs, err := xenapi.Host.GetAll(session)
+	if err != nil {
+		return transportPIFs, errors.New(err.Error())
+	}
+	for _, hostRef := range hostRefs {
+		if _, ok := transportPIFs[hostRef]; !ok {
+			hostname, err := xenapi.Host.GetHostname(session, hostRef)
+			if err != nil {
+				return transportPIFs, errors.New(err.Error())
+			}
+			return transportPIFs, fmt.Errorf("unable to find the transport PIF on host %s", hostname)
+		}
+	}
+	return transportPIFs, nil
+}
+
+// getTunnelsByHost returns the tunnels of the network on each host
+func getTunnelsByHost(session *xenapi.Session, networkRef xenapi.NetworkRef) (map[xenapi.HostRef]xenapi.TunnelRef, error) {
+	tunnels := make(map[xenapi.HostRef]xenapi.TunnelRef)
+	pifRefs, err := xenapi.Network.GetPIFs(session, networkRef)
+	if err != nil {
+		return tunnels, errors.New(err.Error())
+	}
+	for _, pifRef := range pifRefs {
+		pifRecord, err := xenapi.PIF.GetRecord(session, pifRef)
+		if err != nil {
+			return tunnels, errors.New(err.Error())
+		}
+		for _, tunnelRef := range pifRecord.TunnelAccessPIFOf {
+			tunnels[pifRecord.Host] = tunnelRef
+		}
+	}
+	return tunnels, nil
+}
+
+// createTunnels creates the tunnel on the hosts which don't have one for the network yet
+func createTunnels(session *xenapi.Session, networkRef xenapi.NetworkRef, data tunnelResourceModel) error {
+	transportPIFs, err := getTunnelTransportPIFs(session, data.NIC.ValueString())
+	if err != nil {
+		return err
+	}
+	tunnels, err := getTunnelsByHost(session, networkRef)
+	if err != nil {
+		return err
+	}
+	for hostRef, pifRef := range transportPIFs {
+		if _, ok := tunnels[hostRef]; ok {
+			continue
+		}
+		_, err = xenapi.Tunnel.Create(session, pifRef, networkRef, xenapi.TunnelProtocol(data.Protocol.ValueString()))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// getTunnelStatus returns the tunnel active status keyed by the host UUID
+func getTunnelStatus(session *xenapi.Session, tunnels map[xenapi.HostRef]xenapi.TunnelRef) (map[string]string, error) {
+	status := make(map[string]string)
+	for hostRef, tunnelRef := range tunnels {
+		hostUUID, err := xenapi.Host.GetUUID(session, hostRef)
+		if err != nil {
+			return status, errors.New(err.Error())
+		}
+		tunnelStatus, err := xenapi.Tunnel.GetStatus(session, tunnelRef)
+		if err != nil {
+			return status, errors.New(err.Error())
+		}
+		status[hostUUID] = tunnelStatus["active"]
+	}
+	return status, nil
+}
+
+// isTunnelHostsChanged returns true if the hosts in the pool are different from the hosts in status
+func isTunnelHostsChanged(ctx context.Context, session *xenapi.Session, status types.Map) (bool, error) {
+	statusMap := make(map[string]string)
+	diags := status.ElementsAs(ctx, &statusMap, false)
+	if diags.HasError() {
+		return false, errors.New("unable to access tunnel status")
+	}
+	hostRecords, err := xenapi.Host.GetAllRecords(session)
+	if err != nil {
+		return false, errors.New(err.Error())
+	}
+	if len(hostRecords) != len(statusMap) {
+		return true, nil
+	}
+	for _, hostRecord := range hostRecords {
+		if _, ok := statusMap[hostRecord.UUID]; !ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func updateTunnelResourceModel(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, record xenapi.NetworkRecord, data *tunnelResourceModel) error {
+	data.NameLabel = types.StringValue(record.NameLabel)
+	return updateTunnelResourceModelComputed(ctx, session, ref, record, data)
+}
+
+func updateTunnelResourceModelComputed(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, record xenapi.NetworkRecord, data *tunnelResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	mtu, err := ToInt32(record.MTU)
+	if err != nil {
+		return err
+	}
+	data.MTU = types.Int32Value(mtu)
+	var diags diag.Diagnostics
+	data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
+	if diags.HasError() {
+		return errors.New("unable to update data for network_tunnel other_config")
+	}
+
+	tunnels, err := getTunnelsByHost(session, ref)
+	if err != nil {
+		return err
+	}
+	if len(tunnels) == 0 {
+		return errors.New("unable to find tunnel on network " + record.NameLabel)
+	}
+	for _, tunnelRef := range tunnels {
+		protocol, err := xenapi.Tunnel.GetProtocol(session, tunnelRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		data.Protocol = types.StringValue(string(protocol))
+		break
+	}
+	status, err := getTunnelStatus(session, tunnels)
+	if err != nil {
+		return err
+	}
+	data.Status, diags = types.MapValueFrom(ctx, types.StringType, status)
+	if diags.HasError() {
+		return errors.New("unable to update data for network_tunnel status")
+	}
+
+	return nil
+}
+
+func tunnelResourceModelUpdateCheck(data tunnelResourceModel, dataState tunnelResourceModel) error {
+	if data.NIC != dataState.NIC {
+		return errors.New(`"nic" doesn't expected to be updated`)
+	}
+	if data.Protocol != dataState.Protocol {
+		return errors.New(`"protocol" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+func tunnelResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref xenapi.NetworkRef, data tunnelResourceModel) error {
+	err := xenapi.Network.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.Network.SetMTU(session, ref, int(data.MTU.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	otherConfig := make(map[string]string)
+	diags := data.OtherConfig.ElementsAs(ctx, &otherConfig, false)
+	if diags.HasError() {
+		return errors.New("unable to access network other config")
+	}
+	err = xenapi.Network.SetOtherConfig(session, ref, otherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	// hosts may have joined the pool since the tunnels were created
+	return createTunnels(session, ref, data)
+}
+
+func cleanupTunnelResource(session *xenapi.Session, ref xenapi.NetworkRef) error {
+	tunnels, err := getTunnelsByHost(session, ref)
+	if err != nil {
+		return err
+	}
+	for _, tunnelRef := range tunnels {
+		err = xenapi.Tunnel.Destroy(session, tunnelRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	err = xenapi.Network.Destroy(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
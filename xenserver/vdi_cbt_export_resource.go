@@ -0,0 +1,177 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource              = &vdiCbtExportResource{}
+	_ resource.ResourceWithConfigure = &vdiCbtExportResource{}
+)
+
+func NewVDICbtExportResource() resource.Resource {
+	return &vdiCbtExportResource{}
+}
+
+// vdiCbtExportResource defines the resource implementation.
+type vdiCbtExportResource struct {
+	session *xenapi.Session
+}
+
+func (r *vdiCbtExportResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vdi_cbt_export"
+}
+
+func (r *vdiCbtExportResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a resource to export the changed blocks between two snapshots of a virtual disk image to a local file, the changed block tracking should be enabled on the virtual disk image by `cbt_enabled` of `xenserver_vdi` before the snapshots are taken." +
+			"\n\n-> **Note:** The output file is a sparse raw image which has the size of `vdi_to_uuid`, only the changed blocks are written at their offsets. Use the bitmap in `bitmap_file` to tell the changed blocks from the unchanged blocks when merging it to the baseline." +
+			"\n\n-> **Note:** The changed blocks are read from the NBD server of the host, set `purpose` of a host network to `\"nbd\"` or `\"insecure_nbd\"` by `xenserver_network` first." +
+			"\n\n-> **Note:** The existing files are never overwritten, remove both the output file and the bitmap file before the export is created again. The resource is created again if either file is removed, and the files are kept when the resource is destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"vdi_from_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the baseline snapshot VDI, for example, a value of `vdis` of `xenserver_snapshot`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vdi_to_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the snapshot VDI to export the changed blocks from.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"output_file": schema.StringAttribute{
+				MarkdownDescription: "The path of the local file to write the changed blocks to, the file and the bitmap file should not exist.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"block_size": schema.Int64Attribute{
+				MarkdownDescription: "The size of the block (in bytes) which each bit of the bitmap in `bitmap_file` represents.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"bitmap_file": schema.StringAttribute{
+				MarkdownDescription: "The path of the local file which the bitmap of the changed blocks is written to, it's `output_file` with the `.bitmap` suffix. Each bit represents a block, the first block is the most significant bit of the first byte.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"changed_block_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of the changed blocks.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the export.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *vdiCbtExportResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *vdiCbtExportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data vdiCbtExportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Exporting VDI changed blocks...")
+	err := exportVDIChangedBlocks(ctx, r.session, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to export VDI changed blocks",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "VDI changed blocks exported")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *vdiCbtExportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data vdiCbtExportResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Export again when the output file or the bitmap file is removed
+	for _, name := range []string{data.OutputFile.ValueString(), data.BitmapFile.ValueString()} {
+		_, err := os.Stat(name)
+		if errors.Is(err, os.ErrNotExist) {
+			tflog.Warn(ctx, "The file "+name+" doesn't exist, removing the resource from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read the exported file",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *vdiCbtExportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan vdiCbtExportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All the configurable attributes require replacement, nothing to update
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vdiCbtExportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The output file and the bitmap file are kept, only the resource is removed from state
+	tflog.Debug(ctx, "Removing VDI changed blocks export from state")
+}
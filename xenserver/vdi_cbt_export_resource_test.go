@@ -0,0 +1,124 @@
+package xenserver
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccVDICbtExportResourceConfig(output_file string) string {
+	return fmt.Sprintf(`
+data "xenserver_sr" "sr" {
+	name_label = "Local storage"
+}
+
+resource "xenserver_vdi" "vdi" {
+	name_label   = "A test CBT vdi"
+	sr_uuid      = data.xenserver_sr.sr.data_items[0].uuid
+	virtual_size = 1 * 1024 * 1024 * 1024
+	cbt_enabled  = true
+}
+
+data "xenserver_network" "network" {}
+
+resource "xenserver_network" "nbd" {
+	name_label = "A test NBD network"
+	nic        = "NIC 1"
+	purpose    = ["nbd"]
+}
+
+resource "xenserver_vm" "vm" {
+	name_label     = "A test virtual-machine"
+	template_name  = "Windows 11"
+	static_mem_max = 4 * 1024 * 1024 * 1024
+	vcpus          = 2
+	hard_drive = [
+		{
+		vdi_uuid = xenserver_vdi.vdi.uuid,
+		mode     = "RW"
+		},
+	]
+	network_interface = [
+		{
+		device       = "0"
+		network_uuid = data.xenserver_network.network.data_items[1].uuid,
+		},
+	]
+}
+
+resource "xenserver_snapshot" "base" {
+	name_label = "Test CBT base snapshot"
+	vm_uuid    = xenserver_vm.vm.uuid
+}
+
+resource "xenserver_snapshot" "next" {
+	name_label = "Test CBT next snapshot"
+	vm_uuid    = xenserver_vm.vm.uuid
+	depends_on = [xenserver_snapshot.base]
+}
+
+resource "xenserver_vdi_cbt_export" "test_export" {
+	vdi_from_uuid = xenserver_snapshot.base.vdis[xenserver_vdi.vdi.uuid]
+	vdi_to_uuid   = xenserver_snapshot.next.vdis[xenserver_vdi.vdi.uuid]
+	output_file   = "%s"
+	depends_on    = [xenserver_network.nbd]
+}
+`, output_file)
+}
+
+func TestAccVDICbtExportResource(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "changed.raw")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVDICbtExportResourceConfig(outputFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi_cbt_export.test_export", "block_size", "65536"),
+					resource.TestCheckResourceAttr("xenserver_vdi_cbt_export.test_export", "output_file", outputFile),
+					resource.TestCheckResourceAttr("xenserver_vdi_cbt_export.test_export", "bitmap_file", outputFile+".bitmap"),
+					resource.TestCheckResourceAttrSet("xenserver_vdi_cbt_export.test_export", "changed_block_count"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestGetVDIChangedRanges(t *testing.T) {
+	// Blocks 0, 1, 2 and 7 of the first byte, and block 15 of the second byte are changed
+	bitmap := []byte{0xe1, 0x01}
+	ranges := getVDIChangedRanges(bitmap, 16*vdiCbtBlockSize)
+	expected := []vdiBlockRange{
+		{Offset: 0, Length: 3 * vdiCbtBlockSize},
+		{Offset: 7 * vdiCbtBlockSize, Length: vdiCbtBlockSize},
+		{Offset: 15 * vdiCbtBlockSize, Length: vdiCbtBlockSize},
+	}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("expected %v, got %v", expected, ranges)
+	}
+
+	// The last block is cut at the virtual size, and the blocks after it are ignored
+	ranges = getVDIChangedRanges(bitmap, 7*vdiCbtBlockSize+512)
+	expected = []vdiBlockRange{
+		{Offset: 0, Length: 3 * vdiCbtBlockSize},
+		{Offset: 7 * vdiCbtBlockSize, Length: 512},
+	}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("expected %v, got %v", expected, ranges)
+	}
+
+	// The range is split at the max read size
+	bitmap = make([]byte, 16)
+	for i := range bitmap {
+		bitmap[i] = 0xff
+	}
+	ranges = getVDIChangedRanges(bitmap, 128*vdiCbtBlockSize)
+	if len(ranges) != 2 || ranges[0].Length != nbdMaxReadSize || ranges[1].Offset != nbdMaxReadSize {
+		t.Fatalf("expected 2 ranges split at %d, got %v", nbdMaxReadSize, ranges)
+	}
+}
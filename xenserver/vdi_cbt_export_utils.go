@@ -0,0 +1,409 @@
+package xenserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+// vdiCbtBlockSize is the size of the block which each bit of the changed block bitmap represents
+const vdiCbtBlockSize = 64 * 1024
+
+// The constants of the NBD protocol, see https://github.com/NetworkBlockDevice/nbd/blob/master/doc/proto.md
+const (
+	nbdMagic             = 0x4e42444d41474943 // "NBDMAGIC"
+	nbdOptionMagic       = 0x49484156454f5054 // "IHAVEOPT"
+	nbdOptionReplyMagic  = 0x3e889045565a9
+	nbdRequestMagic      = 0x25609513
+	nbdSimpleReplyMagic  = 0x67446698
+	nbdFlagFixedNewstyle = 1 << 0
+	nbdFlagNoZeroes      = 1 << 1
+	nbdOptExportName     = 1
+	nbdOptStartTLS       = 5
+	nbdRepAck            = 1
+	nbdCmdRead           = 0
+	nbdCmdDisc           = 2
+	// nbdMaxReadSize is the max length of a read request, the longer changed ranges are split
+	nbdMaxReadSize = 4 * 1024 * 1024
+)
+
+type vdiCbtExportResourceModel struct {
+	VDIFrom           types.String `tfsdk:"vdi_from_uuid"`
+	VDITo             types.String `tfsdk:"vdi_to_uuid"`
+	OutputFile        types.String `tfsdk:"output_file"`
+	BlockSize         types.Int64  `tfsdk:"block_size"`
+	BitmapFile        types.String `tfsdk:"bitmap_file"`
+	ChangedBlockCount types.Int64  `tfsdk:"changed_block_count"`
+	ID                types.String `tfsdk:"id"`
+}
+
+// vdiBlockRange is a range of the adjacent changed blocks
+type vdiBlockRange struct {
+	Offset int64
+	Length int64
+}
+
+// isVDIBlockChanged returns true if the block is marked as changed in the bitmap, the first block is the most significant bit
+func isVDIBlockChanged(bitmap []byte, block int) bool {
+	if block/8 >= len(bitmap) {
+		return false
+	}
+	return bitmap[block/8]&(0x80>>(block%8)) != 0
+}
+
+// getVDIChangedRanges merges the adjacent changed blocks into ranges up to nbdMaxReadSize, the ranges end at the virtual size
+func getVDIChangedRanges(bitmap []byte, virtualSize int64) []vdiBlockRange {
+	var ranges []vdiBlockRange
+	for block := 0; block < len(bitmap)*8; block++ {
+		offset := int64(block) * vdiCbtBlockSize
+		if offset >= virtualSize {
+			break
+		}
+		if !isVDIBlockChanged(bitmap, block) {
+			continue
+		}
+		length := min(int64(vdiCbtBlockSize), virtualSize-offset)
+		last := len(ranges) - 1
+		if last >= 0 && ranges[last].Offset+ranges[last].Length == offset && ranges[last].Length+length <= nbdMaxReadSize {
+			ranges[last].Length += length
+			continue
+		}
+		ranges = append(ranges, vdiBlockRange{Offset: offset, Length: length})
+	}
+	return ranges
+}
+
+// getVDICbtBitmapFile returns the file which the changed block bitmap is written to, it's next to the output file
+func getVDICbtBitmapFile(outputFile string) string {
+	return filepath.Clean(outputFile) + ".bitmap"
+}
+
+// exportVDIChangedBlocks writes the blocks of "vdi_to_uuid" which are changed since "vdi_from_uuid" to the output file
+// at their offsets, the output file is a sparse raw image which has the virtual size of "vdi_to_uuid".
+// The changed block bitmap is written to the bitmap file.
+func exportVDIChangedBlocks(ctx context.Context, session *xenapi.Session, data *vdiCbtExportResourceModel) error {
+	vdiFromRef, err := xenapi.VDI.GetByUUID(session, data.VDIFrom.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	vdiToRef, err := xenapi.VDI.GetByUUID(session, data.VDITo.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	changedBlocks, err := xenapi.VDI.ListChangedBlocks(session, vdiFromRef, vdiToRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	bitmap, err := base64.StdEncoding.DecodeString(changedBlocks)
+	if err != nil {
+		return errors.New("unable to decode the changed block bitmap. " + err.Error())
+	}
+	changedBlockCount := 0
+	for block := 0; block < len(bitmap)*8; block++ {
+		if isVDIBlockChanged(bitmap, block) {
+			changedBlockCount++
+		}
+	}
+	virtualSize, err := xenapi.VDI.GetVirtualSize(session, vdiToRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	// The existing files are never overwritten, and the files are removed if the export fails
+	outputFile := filepath.Clean(data.OutputFile.ValueString())
+	file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	defer file.Close()
+	bitmapFile := getVDICbtBitmapFile(outputFile)
+	err = writeNewFile(bitmapFile, bitmap)
+	if err != nil {
+		os.Remove(outputFile)
+		return err
+	}
+	err = writeVDIChangedBlocksFile(ctx, session, vdiToRef, bitmap, int64(virtualSize), file)
+	if err != nil {
+		os.Remove(outputFile)
+		os.Remove(bitmapFile)
+		return err
+	}
+
+	data.BlockSize = types.Int64Value(vdiCbtBlockSize)
+	data.BitmapFile = types.StringValue(bitmapFile)
+	data.ChangedBlockCount = types.Int64Value(int64(changedBlockCount))
+	data.ID = types.StringValue(data.VDITo.ValueString())
+	return nil
+}
+
+// writeVDIChangedBlocksFile writes the changed blocks to the output file which has the virtual size of the VDI
+func writeVDIChangedBlocksFile(ctx context.Context, session *xenapi.Session, vdiRef xenapi.VDIRef, bitmap []byte, virtualSize int64, file *os.File) error {
+	err := file.Truncate(virtualSize)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	ranges := getVDIChangedRanges(bitmap, virtualSize)
+	if len(ranges) == 0 {
+		return nil
+	}
+	return writeVDIChangedBlocks(ctx, session, vdiRef, ranges, file)
+}
+
+// writeNewFile writes the data to a new file, it fails if the file exists
+func writeNewFile(name string, data []byte) error {
+	file, err := os.OpenFile(filepath.Clean(name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	_, err = file.Write(data)
+	errClose := file.Close()
+	if err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(name)
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// writeVDIChangedBlocks reads the changed ranges of the VDI from the NBD server of the host, and writes them at their offsets
+func writeVDIChangedBlocks(ctx context.Context, session *xenapi.Session, vdiRef xenapi.VDIRef, ranges []vdiBlockRange, file *os.File) error {
+	client, err := connectVDINbdServer(ctx, session, vdiRef)
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	buffer := make([]byte, nbdMaxReadSize)
+	for _, blockRange := range ranges {
+		err = client.readAt(buffer[:blockRange.Length], uint64(blockRange.Offset)) // #nosec G115
+		if err != nil {
+			return err
+		}
+		_, err = file.WriteAt(buffer[:blockRange.Length], blockRange.Offset)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+// nbdClient is a minimal NBD client which reads the export over the fixed newstyle negotiation
+type nbdClient struct {
+	conn   net.Conn
+	handle uint64
+}
+
+// connectVDINbdServer connects to the NBD servers returned by VDI.get_nbd_info in order until one succeeds,
+// the network of the host should have the "nbd" or "insecure_nbd" purpose.
+func connectVDINbdServer(ctx context.Context, session *xenapi.Session, vdiRef xenapi.VDIRef) (*nbdClient, error) {
+	infos, err := xenapi.VDI.GetNbdInfo(session, vdiRef)
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	if len(infos) == 0 {
+		return nil, errors.New(`unable to find the NBD server of the VDI, set "purpose" of a host network to "nbd" or "insecure_nbd"`)
+	}
+	var errMsgs []string
+	for _, info := range infos {
+		client, err := newNbdClient(ctx, info)
+		if err == nil {
+			return client, nil
+		}
+		errMsgs = append(errMsgs, err.Error())
+	}
+	return nil, errors.New("unable to connect to the NBD server of the VDI, " + strings.Join(errMsgs, "\n"))
+}
+
+func newNbdClient(ctx context.Context, info xenapi.VdiNbdServerInfoRecord) (*nbdClient, error) {
+	dialer := net.Dialer{Timeout: 30 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(info.Address, strconv.Itoa(info.Port)))
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	client := &nbdClient{conn: conn}
+	err = client.negotiate(ctx, info)
+	if err != nil {
+		client.conn.Close()
+		return nil, fmt.Errorf("%s:%d %s", info.Address, info.Port, err.Error())
+	}
+	return client, nil
+}
+
+// negotiate does the fixed newstyle handshake, upgrades the connection to TLS when the server has a certificate,
+// and selects the export
+func (c *nbdClient) negotiate(ctx context.Context, info xenapi.VdiNbdServerInfoRecord) error {
+	var greeting struct {
+		Magic       uint64
+		OptionMagic uint64
+		Flags       uint16
+	}
+	err := binary.Read(c.conn, binary.BigEndian, &greeting)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if greeting.Magic != nbdMagic || greeting.OptionMagic != nbdOptionMagic || greeting.Flags&nbdFlagFixedNewstyle == 0 {
+		return errors.New("the server doesn't support the NBD fixed newstyle negotiation")
+	}
+	noZeroes := greeting.Flags&nbdFlagNoZeroes != 0
+	clientFlags := uint32(nbdFlagFixedNewstyle)
+	if noZeroes {
+		clientFlags |= nbdFlagNoZeroes
+	}
+	err = binary.Write(c.conn, binary.BigEndian, clientFlags)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if info.Cert != "" {
+		err = c.startTLS(ctx, info)
+		if err != nil {
+			return err
+		}
+	}
+	err = c.sendOption(nbdOptExportName, []byte(info.Exportname))
+	if err != nil {
+		return err
+	}
+	var export struct {
+		Size  uint64
+		Flags uint16
+	}
+	err = binary.Read(c.conn, binary.BigEndian, &export)
+	if err != nil {
+		return errors.New("unable to open the NBD export " + info.Exportname + ", " + err.Error())
+	}
+	if !noZeroes {
+		_, err = io.CopyN(io.Discard, c.conn, 124)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *nbdClient) sendOption(option uint32, data []byte) error {
+	var request bytes.Buffer
+	_ = binary.Write(&request, binary.BigEndian, uint64(nbdOptionMagic))
+	_ = binary.Write(&request, binary.BigEndian, option)
+	_ = binary.Write(&request, binary.BigEndian, uint32(len(data))) // #nosec G115
+	request.Write(data)
+	_, err := c.conn.Write(request.Bytes())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// startTLS upgrades the connection to TLS, the server certificate is pinned to the certificate from VDI.get_nbd_info
+func (c *nbdClient) startTLS(ctx context.Context, info xenapi.VdiNbdServerInfoRecord) error {
+	err := c.sendOption(nbdOptStartTLS, nil)
+	if err != nil {
+		return err
+	}
+	var reply struct {
+		Magic  uint64
+		Option uint32
+		Type   uint32
+		Length uint32
+	}
+	err = binary.Read(c.conn, binary.BigEndian, &reply)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	_, err = io.CopyN(io.Discard, c.conn, int64(reply.Length))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if reply.Magic != nbdOptionReplyMagic || reply.Option != nbdOptStartTLS || reply.Type != nbdRepAck {
+		return errors.New("the NBD server refused to start TLS")
+	}
+	block, _ := pem.Decode([]byte(info.Cert))
+	if block == nil {
+		return errors.New("unable to decode the certificate of the NBD server")
+	}
+	tlsConn := tls.Client(c.conn, &tls.Config{
+		ServerName: info.Subject,
+		MinVersion: tls.VersionTLS12,
+		// The certificate chain isn't verified, the server certificate is compared with the one from XAPI instead
+		InsecureSkipVerify: true, // #nosec G402
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], block.Bytes) {
+				return errors.New("the certificate of the NBD server doesn't match the certificate from XAPI")
+			}
+			return nil
+		},
+	})
+	err = tlsConn.HandshakeContext(ctx)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	c.conn = tlsConn
+	return nil
+}
+
+// readAt reads the length of the buffer from the offset of the export
+func (c *nbdClient) readAt(buffer []byte, offset uint64) error {
+	c.handle++
+	var request bytes.Buffer
+	_ = binary.Write(&request, binary.BigEndian, uint32(nbdRequestMagic))
+	_ = binary.Write(&request, binary.BigEndian, uint16(0))
+	_ = binary.Write(&request, binary.BigEndian, uint16(nbdCmdRead))
+	_ = binary.Write(&request, binary.BigEndian, c.handle)
+	_ = binary.Write(&request, binary.BigEndian, offset)
+	_ = binary.Write(&request, binary.BigEndian, uint32(len(buffer))) // #nosec G115
+	_, err := c.conn.Write(request.Bytes())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	var reply struct {
+		Magic  uint32
+		Error  uint32
+		Handle uint64
+	}
+	err = binary.Read(c.conn, binary.BigEndian, &reply)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if reply.Magic != nbdSimpleReplyMagic || reply.Handle != c.handle {
+		return errors.New("unexpected reply from the NBD server")
+	}
+	if reply.Error != 0 {
+		return fmt.Errorf("unable to read %d bytes at offset %d from the NBD server, error %d", len(buffer), offset, reply.Error)
+	}
+	_, err = io.ReadFull(c.conn, buffer)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// close sends the disconnect request and closes the connection
+func (c *nbdClient) close() {
+	var request bytes.Buffer
+	_ = binary.Write(&request, binary.BigEndian, uint32(nbdRequestMagic))
+	_ = binary.Write(&request, binary.BigEndian, uint16(0))
+	_ = binary.Write(&request, binary.BigEndian, uint16(nbdCmdDisc))
+	_ = binary.Write(&request, binary.BigEndian, c.handle+1)
+	_ = binary.Write(&request, binary.BigEndian, uint64(0))
+	_ = binary.Write(&request, binary.BigEndian, uint32(0))
+	_, _ = c.conn.Write(request.Bytes())
+	c.conn.Close()
+}
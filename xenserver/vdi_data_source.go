@@ -0,0 +1,296 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &vdiDataSource{}
+	_ datasource.DataSourceWithConfigure = &vdiDataSource{}
+)
+
+// NewVDIDataSource is a helper function to simplify the provider implementation.
+func NewVDIDataSource() datasource.DataSource {
+	return &vdiDataSource{}
+}
+
+// vdiDataSource is the data source implementation.
+type vdiDataSource struct {
+	session *xenapi.Session
+}
+
+// Metadata returns the data source type name.
+func (d *vdiDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vdi"
+}
+
+// Schema defines the schema for the data source.
+func (d *vdiDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides information about the virtual disk images (VDI), for example, the ISOs in the ISO libraries, or the disks which are not attached to any VM.",
+
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the virtual disk image.",
+				Optional:            true,
+			},
+			"sr_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the storage repository which the virtual disk image is in.",
+				Optional:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the virtual disk image, for example, `\"user\"`, `\"system\"`.",
+				Optional:            true,
+			},
+			"is_a_snapshot": schema.BoolAttribute{
+				MarkdownDescription: "True if the virtual disk image is a snapshot.",
+				Optional:            true,
+			},
+			"tags": schema.ListAttribute{
+				MarkdownDescription: "The tags which the virtual disk image should have, all the tags should be matched.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"other_config": schema.MapAttribute{
+				MarkdownDescription: "The key-value pairs which the additional configuration of the virtual disk image should have, all the pairs should be matched.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"vm_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the VM which the virtual disk image is attached to.",
+				Optional:            true,
+			},
+			"filter":       filterSchema(vdiRecordData{}),
+			"expect_count": expectCountSchema(),
+			"data_items": schema.ListNestedAttribute{
+				MarkdownDescription: "The return items of virtual disk images.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the virtual disk image.",
+							Computed:            true,
+						},
+						"name_label": schema.StringAttribute{
+							MarkdownDescription: "The name of the virtual disk image.",
+							Computed:            true,
+						},
+						"name_description": schema.StringAttribute{
+							MarkdownDescription: "The description of the virtual disk image.",
+							Computed:            true,
+						},
+						"sr_uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the storage repository which the virtual disk image is in.",
+							Computed:            true,
+						},
+						"virtual_size": schema.Int64Attribute{
+							MarkdownDescription: "The size of the virtual disk image (in bytes) as seen by the VMs.",
+							Computed:            true,
+						},
+						"physical_utilisation": schema.Int64Attribute{
+							MarkdownDescription: "The physical space (in bytes) used by the virtual disk image on the storage repository.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of the virtual disk image.",
+							Computed:            true,
+						},
+						"sharable": schema.BoolAttribute{
+							MarkdownDescription: "True if the virtual disk image may be shared.",
+							Computed:            true,
+						},
+						"read_only": schema.BoolAttribute{
+							MarkdownDescription: "True if the virtual disk image can only be mounted read-only.",
+							Computed:            true,
+						},
+						"managed": schema.BoolAttribute{
+							MarkdownDescription: "True if the virtual disk image is managed by the storage repository.",
+							Computed:            true,
+						},
+						"missing": schema.BoolAttribute{
+							MarkdownDescription: "True if the storage repository scan reported that the virtual disk image is not present.",
+							Computed:            true,
+						},
+						"location": schema.StringAttribute{
+							MarkdownDescription: "The location information of the virtual disk image.",
+							Computed:            true,
+						},
+						"is_a_snapshot": schema.BoolAttribute{
+							MarkdownDescription: "True if the virtual disk image is a snapshot.",
+							Computed:            true,
+						},
+						"snapshot_of": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the virtual disk image which this snapshot is of.",
+							Computed:            true,
+						},
+						"snapshots": schema.ListAttribute{
+							MarkdownDescription: "The UUID list of the snapshots of the virtual disk image.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"is_tools_iso": schema.BoolAttribute{
+							MarkdownDescription: "True if the virtual disk image is the tools ISO.",
+							Computed:            true,
+						},
+						"cbt_enabled": schema.BoolAttribute{
+							MarkdownDescription: "True if changed blocks are tracked for the virtual disk image.",
+							Computed:            true,
+						},
+						"tags": schema.ListAttribute{
+							MarkdownDescription: "The user-specified tags for categorization purposes.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"other_config": schema.MapAttribute{
+							MarkdownDescription: "The additional configuration of the virtual disk image.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"sm_config": schema.MapAttribute{
+							MarkdownDescription: "The SM dependent data of the virtual disk image.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"vbds": schema.ListAttribute{
+							MarkdownDescription: "The UUID list of the VBDs which the virtual disk image is attached by.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"vms": schema.ListAttribute{
+							MarkdownDescription: "The UUID list of the VMs which the virtual disk image is attached to.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *vdiDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.session = providerData.session
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *vdiDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data vdiDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var srRef xenapi.SRRef
+	if !data.SR.IsNull() {
+		var err error
+		srRef, err = xenapi.SR.GetByUUID(d.session, data.SR.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get SR reference",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	vdiRecords, err := xenapi.VDI.GetAllRecords(d.session)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read VDI records",
+			err.Error(),
+		)
+		return
+	}
+
+	var vdiItems []vdiRecordData
+	for _, vdiRecord := range vdiRecords {
+		if !data.SR.IsNull() && vdiRecord.SR != srRef {
+			continue
+		}
+		matched, err := matchVDIRecord(ctx, vdiRecord, data)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to match VDI record",
+				err.Error(),
+			)
+			return
+		}
+		if !matched {
+			continue
+		}
+
+		var vdiData vdiRecordData
+		err = updateVDIRecordData(ctx, d.session, vdiRecord, &vdiData)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to update VDI record data",
+				err.Error(),
+			)
+			return
+		}
+		if !data.VM.IsNull() {
+			var vms []string
+			resp.Diagnostics.Append(vdiData.VMs.ElementsAs(ctx, &vms, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if !slices.Contains(vms, data.VM.ValueString()) {
+				continue
+			}
+		}
+		matched, err = matchFilters(vdiData, data.Filter)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to filter VDI record data",
+				err.Error(),
+			)
+			return
+		}
+		if !matched {
+			continue
+		}
+		vdiItems = append(vdiItems, vdiData)
+	}
+
+	sort.Slice(vdiItems, func(i, j int) bool {
+		return vdiItems[i].UUID.ValueString() < vdiItems[j].UUID.ValueString()
+	})
+	err = checkExpectCount(data.ExpectCount, len(vdiItems))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unexpected number of VDIs",
+			err.Error(),
+		)
+		return
+	}
+	data.DataItems = vdiItems
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
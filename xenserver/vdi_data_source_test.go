@@ -0,0 +1,70 @@
+package xenserver
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccVDIDataSourceConfig(extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_sr_nfs" "nfs" {
+	name_label       = "test NFS SR"
+	version          = "3"
+	storage_location = "%s"
+}
+
+resource "xenserver_vdi" "test_vdi" {
+	name_label   = "Test VDI data source"
+	sr_uuid      = xenserver_sr_nfs.nfs.uuid
+	virtual_size = 1 * 1024 * 1024 * 1024
+	other_config = {
+		"flag" = "1"
+	}
+}
+
+data "xenserver_vdi" "test_vdi_data" {
+	sr_uuid = xenserver_vdi.test_vdi.sr_uuid
+	%s
+}
+`, os.Getenv("NFS_SERVER")+":"+os.Getenv("NFS_SERVER_PATH"), extra_config)
+}
+
+func TestAccVDIDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccVDIDataSourceConfig(`name_label = "Test VDI data source"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xenserver_vdi.test_vdi_data", "data_items.#", "1"),
+					resource.TestCheckResourceAttrPair("data.xenserver_vdi.test_vdi_data", "data_items.0.uuid", "xenserver_vdi.test_vdi", "uuid"),
+					resource.TestCheckResourceAttr("data.xenserver_vdi.test_vdi_data", "data_items.0.virtual_size", "1073741824"),
+					resource.TestCheckResourceAttr("data.xenserver_vdi.test_vdi_data", "data_items.0.vms.#", "0"),
+				),
+			},
+			{
+				Config: providerConfig + testAccVDIDataSourceConfig(`
+	other_config = {
+		"flag" = "1"
+	}
+	filter = [
+		{
+			name     = "virtual_size"
+			operator = "ge"
+			value    = "1073741824"
+		}
+	]
+	expect_count = 1
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xenserver_vdi.test_vdi_data", "data_items.0.name_label", "Test VDI data source"),
+					resource.TestCheckResourceAttr("data.xenserver_vdi.test_vdi_data", "data_items.0.other_config.flag", "1"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,355 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource              = &vdiImportResource{}
+	_ resource.ResourceWithConfigure = &vdiImportResource{}
+)
+
+func NewVDIImportResource() resource.Resource {
+	return &vdiImportResource{}
+}
+
+// vdiImportResource defines the resource implementation.
+type vdiImportResource struct {
+	session         *xenapi.Session
+	coordinatorConf *coordinatorConf
+}
+
+func (r *vdiImportResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vdi_import"
+}
+
+func (r *vdiImportResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a virtual disk image resource which is imported from a local image file." +
+			"\n\n-> **Note:** The image is uploaded to the `/import_raw_vdi` HTTP handler of the host which can access the storage repository, the qcow2 image is converted to a raw image by `qemu-img` on the local machine before uploading.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the virtual disk image.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the virtual disk image, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"sr_uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the storage repository used." +
+					"\n\n-> **Note:** `sr_uuid` is not allowed to be updated.",
+				Required: true,
+			},
+			"source_file": schema.StringAttribute{
+				MarkdownDescription: "The path of the local image file to import." +
+					"\n\n-> **Note:** The virtual disk image is replaced when `source_file` is updated.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_format": schema.StringAttribute{
+				MarkdownDescription: "The format of the local image file, default to be `\"raw\"`, for example, `\"raw\"`, `\"vhd\"`, `\"qcow2\"`." +
+					"\n\n-> **Note:** The virtual disk image is replaced when `source_format` is updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(vdiImportFormatRaw),
+				Validators: []validator.String{
+					stringvalidator.OneOf(vdiImportFormatRaw, vdiImportFormatVHD, vdiImportFormatQcow2),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_checksum": schema.StringAttribute{
+				MarkdownDescription: "The SHA-256 checksum of the local image file, for example, `filesha256(\"disk.qcow2\")`, the checksum is verified before uploading." +
+					"\n\n-> **Note:** The virtual disk image is replaced when `source_checksum` is updated, set it to replace the virtual disk image when the image file changes.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"virtual_size": schema.Int64Attribute{
+				MarkdownDescription: "The size of virtual disk image (in bytes), default to be the virtual size of the image, it can't be less than the virtual size of the image." +
+					"\n\n-> **Note:** `virtual_size` is not allowed to be updated.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"other_config": schema.MapAttribute{
+				MarkdownDescription: "The additional configuration of the virtual disk image, default to be `{}`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				ElementType:         types.StringType,
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the virtual disk image.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the virtual disk image.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *vdiImportResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+	r.coordinatorConf = &providerData.coordinatorConf
+}
+
+func (r *vdiImportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data vdiImportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Importing VDI...")
+	sourceFile, format, err := prepareVDIImportSource(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to prepare VDI import source",
+			err.Error(),
+		)
+		return
+	}
+	if sourceFile != data.SourceFile.ValueString() {
+		defer os.Remove(sourceFile)
+	}
+	imageSize, err := getVDIImportSize(sourceFile, format)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get the virtual size of VDI import source",
+			err.Error(),
+		)
+		return
+	}
+	record, err := getVDIImportCreateParams(ctx, r.session, data, imageSize)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI create params",
+			err.Error(),
+		)
+		return
+	}
+	vdiRef, err := xenapi.VDI.Create(r.session, record)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create VDI",
+			err.Error(),
+		)
+		return
+	}
+	err = importRawVDI(ctx, r.session, r.coordinatorConf, vdiRef, record.SR, sourceFile, format)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to import VDI",
+			err.Error(),
+		)
+		err = cleanupVDIResource(r.session, vdiRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VDI resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI record",
+			err.Error(),
+		)
+		err = cleanupVDIResource(r.session, vdiRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VDI resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateVDIImportResourceModelComputed(ctx, vdiRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of VDIImportResourceModel",
+			err.Error(),
+		)
+		err = cleanupVDIResource(r.session, vdiRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VDI resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	tflog.Debug(ctx, "VDI imported")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *vdiImportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data vdiImportResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	vdiRef, err := xenapi.VDI.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI ref",
+			err.Error(),
+		)
+		return
+	}
+	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateVDIImportResourceModel(ctx, r.session, vdiRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of VDIImportResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *vdiImportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state vdiImportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checking if configuration changes are allowed
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := vdiImportResourceModelUpdateCheck(plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error update xenserver_vdi_import configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Update the resource with new configuration
+	vdiRef, err := xenapi.VDI.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI ref",
+			err.Error(),
+		)
+		return
+	}
+	err = vdiImportResourceModelUpdate(ctx, r.session, vdiRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update VDI resource",
+			err.Error(),
+		)
+		return
+	}
+	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateVDIImportResourceModelComputed(ctx, vdiRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the computed fields of VDIImportResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vdiImportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data vdiImportResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vdiRef, err := xenapi.VDI.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VDI ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupVDIResource(r.session, vdiRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete VDI resource",
+			err.Error(),
+		)
+		return
+	}
+}
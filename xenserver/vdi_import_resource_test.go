@@ -0,0 +1,80 @@
+package xenserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccVDIImportResourceConfig(name_label string, source_file string, extra_config string) string {
+	return fmt.Sprintf(`
+resource "xenserver_sr_nfs" "nfs" {
+	name_label       = "test NFS SR"
+	version          = "3"
+	storage_location = "%s"
+}
+
+resource "xenserver_vdi_import" "test_vdi_import" {
+	name_label      = "%s"
+	sr_uuid         = xenserver_sr_nfs.nfs.uuid
+	source_file     = "%s"
+	source_checksum = filesha256("%s")
+	%s
+}
+`, os.Getenv("NFS_SERVER")+":"+os.Getenv("NFS_SERVER_PATH"), name_label, source_file, source_file, extra_config)
+}
+
+func TestAccVDIImportResource(t *testing.T) {
+	sourceFile := filepath.Join(t.TempDir(), "disk.raw")
+	err := os.WriteFile(sourceFile, make([]byte, 2*1024*1024), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVDIImportResourceConfig("Test VDI import", sourceFile, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi_import.test_vdi_import", "name_label", "Test VDI import"),
+					resource.TestCheckResourceAttr("xenserver_vdi_import.test_vdi_import", "source_format", "raw"),
+					resource.TestCheckResourceAttr("xenserver_vdi_import.test_vdi_import", "virtual_size", "2097152"),
+					// Verify dynamic values have any value set in the state.
+
+					resource.TestCheckResourceAttrSet("xenserver_vdi_import.test_vdi_import", "uuid"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccVDIImportResourceConfig("Test VDI import 2", sourceFile, `name_description = "Test VDI import description"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi_import.test_vdi_import", "name_label", "Test VDI import 2"),
+					resource.TestCheckResourceAttr("xenserver_vdi_import.test_vdi_import", "name_description", "Test VDI import description"),
+				),
+			},
+			{
+				Config:      providerConfig + testAccVDIImportResourceConfig("Test VDI import 2", sourceFile, `virtual_size = 1024`),
+				ExpectError: regexp.MustCompile(`"virtual_size" doesn't expected to be updated`),
+			},
+			// Replace testing
+			{
+				PreConfig: func() {
+					err := os.WriteFile(sourceFile, make([]byte, 4*1024*1024), 0o600)
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: providerConfig + testAccVDIImportResourceConfig("Test VDI import 2", sourceFile, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi_import.test_vdi_import", "virtual_size", "4194304"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
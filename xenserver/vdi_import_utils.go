@@ -0,0 +1,305 @@
+package xenserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+const (
+	vdiImportFormatRaw   = "raw"
+	vdiImportFormatVHD   = "vhd"
+	vdiImportFormatQcow2 = "qcow2"
+)
+
+type vdiImportResourceModel struct {
+	NameLabel       types.String `tfsdk:"name_label"`
+	NameDescription types.String `tfsdk:"name_description"`
+	SR              types.String `tfsdk:"sr_uuid"`
+	SourceFile      types.String `tfsdk:"source_file"`
+	SourceFormat    types.String `tfsdk:"source_format"`
+	SourceChecksum  types.String `tfsdk:"source_checksum"`
+	VirtualSize     types.Int64  `tfsdk:"virtual_size"`
+	OtherConfig     types.Map    `tfsdk:"other_config"`
+	UUID            types.String `tfsdk:"uuid"`
+	ID              types.String `tfsdk:"id"`
+}
+
+// prepareVDIImportSource checks the source file and returns the file to upload with its format,
+// the qcow2 image is converted to a temporary raw image which should be removed by the caller.
+func prepareVDIImportSource(ctx context.Context, data vdiImportResourceModel) (string, string, error) {
+	sourceFile := data.SourceFile.ValueString()
+	if !data.SourceChecksum.IsNull() && !data.SourceChecksum.IsUnknown() {
+		checksum, err := getFileSHA256(sourceFile)
+		if err != nil {
+			return "", "", err
+		}
+		if !strings.EqualFold(checksum, data.SourceChecksum.ValueString()) {
+			return "", "", fmt.Errorf("the SHA-256 checksum of %s is %s, doesn't match %s", sourceFile, checksum, data.SourceChecksum.ValueString())
+		}
+	}
+	format := data.SourceFormat.ValueString()
+	if format != vdiImportFormatQcow2 {
+		return sourceFile, format, nil
+	}
+	rawFile, err := convertQcow2ToRaw(ctx, sourceFile)
+	if err != nil {
+		return "", "", err
+	}
+	return rawFile, vdiImportFormatRaw, nil
+}
+
+func getFileSHA256(name string) (string, error) {
+	file, err := os.Open(filepath.Clean(name))
+	if err != nil {
+		return "", errors.New(err.Error())
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", errors.New(err.Error())
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// convertQcow2ToRaw converts the qcow2 image with qemu-img, as XAPI only imports raw and VHD images.
+func convertQcow2ToRaw(ctx context.Context, sourceFile string) (string, error) {
+	rawFile, err := os.CreateTemp("", "xenserver-vdi-*.raw")
+	if err != nil {
+		return "", errors.New(err.Error())
+	}
+	rawFile.Close()
+	// #nosec G204 -- the arguments are passed to qemu-img directly without a shell
+	output, err := exec.CommandContext(ctx, "qemu-img", "convert", "-f", "qcow2", "-O", "raw", sourceFile, rawFile.Name()).CombinedOutput()
+	if err != nil {
+		os.Remove(rawFile.Name())
+		return "", fmt.Errorf("unable to convert %s to raw image with qemu-img, %s %s", sourceFile, err.Error(), string(output))
+	}
+	return rawFile.Name(), nil
+}
+
+// getVDIImportSize returns the virtual size of the raw or VHD image
+func getVDIImportSize(name string, format string) (int64, error) {
+	file, err := os.Open(filepath.Clean(name))
+	if err != nil {
+		return 0, errors.New(err.Error())
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, errors.New(err.Error())
+	}
+	if format == vdiImportFormatRaw {
+		return info.Size(), nil
+	}
+	// The VHD footer is the last 512 bytes of the file, the current size is at offset 48
+	if info.Size() < 512 {
+		return 0, fmt.Errorf("%s is not a valid VHD image", name)
+	}
+	footer := make([]byte, 512)
+	_, err = file.ReadAt(footer, info.Size()-512)
+	if err != nil {
+		return 0, errors.New(err.Error())
+	}
+	if string(footer[:8]) != "conectix" {
+		return 0, fmt.Errorf("%s is not a valid VHD image", name)
+	}
+	return int64(binary.BigEndian.Uint64(footer[48:56])), nil // #nosec G115
+}
+
+// getVDITransferURL returns the URL of the HTTP handler on the host which can access the SR, the coordinator is preferred
+func getVDITransferURL(session *xenapi.Session, coordinatorConf *coordinatorConf, srRef xenapi.SRRef, handler string) (*url.URL, error) {
+	host := coordinatorConf.Host
+	if !strings.HasPrefix(host, "http") {
+		host = "https://" + host
+	}
+	transferURL, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	transferURL.Path = handler
+	srRecord, err := xenapi.SR.GetRecord(session, srRef)
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	if srRecord.Shared {
+		return transferURL, nil
+	}
+	coordinatorRef, _, err := getCoordinatorRef(session)
+	if err != nil {
+		return nil, err
+	}
+	for _, pbdRef := range srRecord.PBDs {
+		pbdRecord, err := xenapi.PBD.GetRecord(session, pbdRef)
+		if err != nil {
+			return nil, errors.New(err.Error())
+		}
+		if !pbdRecord.CurrentlyAttached {
+			continue
+		}
+		if pbdRecord.Host == coordinatorRef {
+			return transferURL, nil
+		}
+		address, err := xenapi.Host.GetAddress(session, pbdRecord.Host)
+		if err != nil {
+			return nil, errors.New(err.Error())
+		}
+		if transferURL.Port() != "" {
+			transferURL.Host = net.JoinHostPort(address, transferURL.Port())
+		} else if strings.Contains(address, ":") {
+			transferURL.Host = "[" + address + "]"
+		} else {
+			transferURL.Host = address
+		}
+		return transferURL, nil
+	}
+	return nil, fmt.Errorf("unable to find any host which the SR %s is attached to", srRecord.UUID)
+}
+
+// importRawVDI streams the image into the VDI by the /import_raw_vdi HTTP handler of XAPI
+func importRawVDI(ctx context.Context, session *xenapi.Session, coordinatorConf *coordinatorConf, vdiRef xenapi.VDIRef, srRef xenapi.SRRef, sourceFile string, format string) error {
+	importURL, err := getVDITransferURL(session, coordinatorConf, srRef, "/import_raw_vdi")
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("vdi", string(vdiRef))
+	query.Set("format", format)
+	importURL.RawQuery = query.Encode()
+
+	file, err := os.Open(filepath.Clean(sourceFile))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	resp, err := doVDITransferRequest(ctx, coordinatorConf, http.MethodPut, importURL, file, info.Size())
+	if err != nil {
+		return fmt.Errorf("unable to import %s to VDI, %s", sourceFile, err.Error())
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// doVDITransferRequest sends the request to the HTTP handler of XAPI, the response body should be closed by the caller
+func doVDITransferRequest(ctx context.Context, coordinatorConf *coordinatorConf, method string, transferURL *url.URL, body io.Reader, contentLength int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, transferURL.String(), body)
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	if body != nil {
+		req.ContentLength = contentLength
+	}
+	req.SetBasicAuth(coordinatorConf.Username, coordinatorConf.Password)
+	req.Header.Set("User-Agent", "XenServer Terraform Provider/"+terraformProviderVersion)
+	client := &http.Client{
+		// The server certificate is not verified, the same as the XenAPI session
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+func getVDIImportCreateParams(ctx context.Context, session *xenapi.Session, data vdiImportResourceModel, imageSize int64) (xenapi.VDIRecord, error) {
+	var record xenapi.VDIRecord
+	record.NameLabel = data.NameLabel.ValueString()
+	record.NameDescription = data.NameDescription.ValueString()
+	srRef, err := xenapi.SR.GetByUUID(session, data.SR.ValueString())
+	if err != nil {
+		return record, errors.New(err.Error())
+	}
+	record.SR = srRef
+	record.VirtualSize = int(imageSize)
+	if !data.VirtualSize.IsUnknown() && !data.VirtualSize.IsNull() {
+		if data.VirtualSize.ValueInt64() < imageSize {
+			return record, fmt.Errorf("\"virtual_size\" %d is less than the size %d of the image", data.VirtualSize.ValueInt64(), imageSize)
+		}
+		record.VirtualSize = int(data.VirtualSize.ValueInt64())
+	}
+	record.Type = xenapi.VdiTypeUser
+
+	diags := data.OtherConfig.ElementsAs(ctx, &record.OtherConfig, false)
+	if diags.HasError() {
+		return record, errors.New("unable to access VDI other config")
+	}
+
+	return record, nil
+}
+
+func updateVDIImportResourceModel(ctx context.Context, session *xenapi.Session, record xenapi.VDIRecord, data *vdiImportResourceModel) error {
+	data.NameLabel = types.StringValue(record.NameLabel)
+	srUUID, err := getUUIDFromSRRef(session, record.SR)
+	if err != nil {
+		return err
+	}
+	data.SR = types.StringValue(srUUID)
+
+	return updateVDIImportResourceModelComputed(ctx, record, data)
+}
+
+func updateVDIImportResourceModelComputed(ctx context.Context, record xenapi.VDIRecord, data *vdiImportResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	data.VirtualSize = types.Int64Value(int64(record.VirtualSize))
+	var diags diag.Diagnostics
+	data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
+	if diags.HasError() {
+		return errors.New("unable to access VDI other config")
+	}
+
+	return nil
+}
+
+func vdiImportResourceModelUpdateCheck(data vdiImportResourceModel, dataState vdiImportResourceModel) error {
+	if data.SR != dataState.SR {
+		return errors.New(`"sr_uuid" doesn't expected to be updated`)
+	}
+	if data.VirtualSize != dataState.VirtualSize {
+		return errors.New(`"virtual_size" doesn't expected to be updated`)
+	}
+	return nil
+}
+
+func vdiImportResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref xenapi.VDIRef, data vdiImportResourceModel) error {
+	return vdiResourceModelUpdate(ctx, session, ref, vdiResourceModel{
+		NameLabel:       data.NameLabel,
+		NameDescription: data.NameDescription,
+		OtherConfig:     data.OtherConfig,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"xenapi"
@@ -17,6 +18,7 @@ var (
 	_ resource.Resource                = &vdiResource{}
 	_ resource.ResourceWithConfigure   = &vdiResource{}
 	_ resource.ResourceWithImportState = &vdiResource{}
+	_ resource.ResourceWithModifyPlan  = &vdiResource{}
 )
 
 func NewVDIResource() resource.Resource {
@@ -35,7 +37,7 @@ func (r *vdiResource) Metadata(_ context.Context, req resource.MetadataRequest,
 func (r *vdiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Provides a virtual disk image resource.",
-		Attributes:          vdiSchema(),
+		Attributes:          vdiResourceSchema(),
 	}
 }
 
@@ -64,20 +66,48 @@ func (r *vdiResource) Create(ctx context.Context, req resource.CreateRequest, re
 	}
 
 	tflog.Debug(ctx, "Creating VDI...")
-	record, err := getVDICreateParams(ctx, r.session, data)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to get VDI create params",
-			err.Error(),
-		)
-		return
+	var vdiRef xenapi.VDIRef
+	if !data.SourceVDI.IsNull() {
+		var err error
+		vdiRef, err = createVDIFromSource(ctx, r.session, data)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to create VDI from source VDI",
+				err.Error(),
+			)
+			return
+		}
+	} else {
+		record, err := getVDICreateParams(ctx, r.session, data)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get VDI create params",
+				err.Error(),
+			)
+			return
+		}
+		vdiRef, err = xenapi.VDI.Create(r.session, record)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to create VDI",
+				err.Error(),
+			)
+			return
+		}
 	}
-	vdiRef, err := xenapi.VDI.Create(r.session, record)
+	err := setVDICbtEnabled(r.session, vdiRef, data.CbtEnabled)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to create VDI",
+			"Unable to set VDI changed block tracking",
 			err.Error(),
 		)
+		err = cleanupVDIResource(r.session, vdiRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VDI resource",
+				err.Error(),
+			)
+		}
 		return
 	}
 	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
@@ -173,7 +203,7 @@ func (r *vdiResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	}
 
 	// Update the resource with new configuration
-	vdiRef, err := xenapi.VDI.GetByUUID(r.session, plan.UUID.ValueString())
+	vdiRef, err := xenapi.VDI.GetByUUID(r.session, state.UUID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get VDI ref",
@@ -181,6 +211,32 @@ func (r *vdiResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		)
 		return
 	}
+	if plan.SR != state.SR {
+		newVDIRef, err := migrateVDI(r.session, vdiRef, plan.SR.ValueString())
+		if newVDIRef != "" {
+			// Save the new VDI at once, the old VDI is no longer used by the VMs
+			vdiRef = newVDIRef
+			vdiUUID, errUUID := xenapi.VDI.GetUUID(r.session, vdiRef)
+			if errUUID != nil {
+				resp.Diagnostics.AddError(
+					"Unable to get VDI UUID",
+					errUUID.Error(),
+				)
+				return
+			}
+			state.SR = plan.SR
+			state.UUID = types.StringValue(vdiUUID)
+			state.ID = types.StringValue(vdiUUID)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to migrate VDI",
+				err.Error(),
+			)
+			return
+		}
+	}
 	err = vdiResourceModelUpdate(ctx, r.session, vdiRef, plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -189,6 +245,14 @@ func (r *vdiResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		)
 		return
 	}
+	err = setVDICbtEnabled(r.session, vdiRef, plan.CbtEnabled)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to set VDI changed block tracking",
+			err.Error(),
+		)
+		return
+	}
 	vdiRecord, err := xenapi.VDI.GetRecord(r.session, vdiRef)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -209,6 +273,24 @@ func (r *vdiResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan plans new UUID of the VDI when it's migrated to another SR
+func (r *vdiResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, state vdiResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.SR.IsUnknown() || plan.SR != state.SR {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uuid"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	}
+}
+
 func (r *vdiResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data vdiResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
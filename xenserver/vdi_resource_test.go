@@ -41,6 +41,7 @@ func TestAccVDIResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "name_label", "Test VDI"),
 					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "name_description", ""),
 					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "virtual_size", "1073741824"),
+					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "cbt_enabled", "false"),
 					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "other_config.%", "1"),
 					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "other_config.flag", "1"),
 					// Verify dynamic values have any value set in the state.
@@ -84,6 +85,85 @@ func TestAccVDIResource(t *testing.T) {
 					resource.TestCheckResourceAttrSet("xenserver_vdi.test_vdi", "uuid"),
 				),
 			},
+			// Enable changed block tracking
+			{
+				Config: providerConfig + testAccVDIResourceConfig("Test VDI 2", "Test VDI description", "1 * 1024 * 1024 * 1024", "cbt_enabled = true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "cbt_enabled", "true"),
+				),
+			},
+			// Disable changed block tracking
+			{
+				Config: providerConfig + testAccVDIResourceConfig("Test VDI 2", "Test VDI description", "1 * 1024 * 1024 * 1024", "cbt_enabled = false"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi.test_vdi", "cbt_enabled", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccVDIResourceSourceConfig(sr_uuid string) string {
+	return fmt.Sprintf(`
+resource "xenserver_sr_nfs" "nfs" {
+	name_label       = "test NFS SR"
+	version          = "3"
+	storage_location = "%s"
+}
+
+data "xenserver_sr" "local" {
+	name_label = "Local storage"
+}
+
+resource "xenserver_vdi" "source_vdi" {
+	name_label   = "Test source VDI"
+	sr_uuid      = xenserver_sr_nfs.nfs.uuid
+	virtual_size = 1 * 1024 * 1024 * 1024
+}
+
+resource "xenserver_vdi" "clone_vdi" {
+	name_label      = "Test clone VDI"
+	sr_uuid         = xenserver_sr_nfs.nfs.uuid
+	source_vdi_uuid = xenserver_vdi.source_vdi.uuid
+	source_mode     = "clone"
+}
+
+resource "xenserver_vdi" "copy_vdi" {
+	name_label      = "Test copy VDI"
+	sr_uuid         = %s
+	source_vdi_uuid = xenserver_vdi.source_vdi.uuid
+	virtual_size    = 2 * 1024 * 1024 * 1024
+}
+`, os.Getenv("NFS_SERVER")+":"+os.Getenv("NFS_SERVER_PATH"), sr_uuid)
+}
+
+func TestAccVDIResourceFromSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVDIResourceSourceConfig("data.xenserver_sr.local.data_items[0].uuid"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi.clone_vdi", "name_label", "Test clone VDI"),
+					resource.TestCheckResourceAttr("xenserver_vdi.clone_vdi", "virtual_size", "1073741824"),
+					resource.TestCheckResourceAttrPair("xenserver_vdi.clone_vdi", "sr_uuid", "xenserver_sr_nfs.nfs", "uuid"),
+					resource.TestCheckResourceAttr("xenserver_vdi.copy_vdi", "source_mode", "copy"),
+					resource.TestCheckResourceAttr("xenserver_vdi.copy_vdi", "virtual_size", "2147483648"),
+					resource.TestCheckResourceAttrPair("xenserver_vdi.copy_vdi", "sr_uuid", "data.xenserver_sr.local", "data_items.0.uuid"),
+				),
+			},
+			// Migrate the VDI to another SR
+			{
+				Config: providerConfig + testAccVDIResourceSourceConfig("xenserver_sr_nfs.nfs.uuid"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vdi.copy_vdi", "name_label", "Test copy VDI"),
+					resource.TestCheckResourceAttr("xenserver_vdi.copy_vdi", "virtual_size", "2147483648"),
+					resource.TestCheckResourceAttrPair("xenserver_vdi.copy_vdi", "sr_uuid", "xenserver_sr_nfs.nfs", "uuid"),
+					resource.TestCheckResourceAttrSet("xenserver_vdi.copy_vdi", "uuid"),
+				),
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
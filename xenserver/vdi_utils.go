@@ -3,21 +3,48 @@ package xenserver
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"xenapi"
 )
 
+const (
+	vdiSourceModeClone = "clone"
+	vdiSourceModeCopy  = "copy"
+)
+
 type vdiResourceModel struct {
+	NameLabel       types.String `tfsdk:"name_label"`
+	NameDescription types.String `tfsdk:"name_description"`
+	SR              types.String `tfsdk:"sr_uuid"`
+	SourceVDI       types.String `tfsdk:"source_vdi_uuid"`
+	SourceMode      types.String `tfsdk:"source_mode"`
+	VirtualSize     types.Int64  `tfsdk:"virtual_size"`
+	Type            types.String `tfsdk:"type"`
+	Sharable        types.Bool   `tfsdk:"sharable"`
+	ReadOnly        types.Bool   `tfsdk:"read_only"`
+	CbtEnabled      types.Bool   `tfsdk:"cbt_enabled"`
+	OtherConfig     types.Map    `tfsdk:"other_config"`
+	UUID            types.String `tfsdk:"uuid"`
+	ID              types.String `tfsdk:"id"`
+}
+
+// vdiModel describes the virtual disk image nested in other resources, for example, xenserver_snapshot.revert_vdis
+type vdiModel struct {
 	NameLabel       types.String `tfsdk:"name_label"`
 	NameDescription types.String `tfsdk:"name_description"`
 	SR              types.String `tfsdk:"sr_uuid"`
@@ -25,12 +52,13 @@ type vdiResourceModel struct {
 	Type            types.String `tfsdk:"type"`
 	Sharable        types.Bool   `tfsdk:"sharable"`
 	ReadOnly        types.Bool   `tfsdk:"read_only"`
+	CbtEnabled      types.Bool   `tfsdk:"cbt_enabled"`
 	OtherConfig     types.Map    `tfsdk:"other_config"`
 	UUID            types.String `tfsdk:"uuid"`
 	ID              types.String `tfsdk:"id"`
 }
 
-var vdiResourceModelAttrTypes = map[string]attr.Type{
+var vdiModelAttrTypes = map[string]attr.Type{
 	"name_label":       types.StringType,
 	"name_description": types.StringType,
 	"sr_uuid":          types.StringType,
@@ -38,6 +66,7 @@ var vdiResourceModelAttrTypes = map[string]attr.Type{
 	"type":             types.StringType,
 	"sharable":         types.BoolType,
 	"read_only":        types.BoolType,
+	"cbt_enabled":      types.BoolType,
 	"other_config":     types.MapType{ElemType: types.StringType},
 	"uuid":             types.StringType,
 	"id":               types.StringType,
@@ -86,6 +115,12 @@ func vdiSchema() map[string]schema.Attribute {
 			Computed: true,
 			Default:  booldefault.StaticBool(false),
 		},
+		"cbt_enabled": schema.BoolAttribute{
+			MarkdownDescription: "True if changed blocks are tracked for the virtual disk image, default to be `false`. The changed blocks between two snapshots of the virtual disk image can be exported by `xenserver_vdi_cbt_export`.",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
 		"other_config": schema.MapAttribute{
 			MarkdownDescription: "The additional configuration of the virtual disk image, default to be `{}`.",
 			Optional:            true,
@@ -110,6 +145,49 @@ func vdiSchema() map[string]schema.Attribute {
 	}
 }
 
+// vdiResourceSchema returns the schema of xenserver_vdi, the VDI can be created from another VDI and migrated to another SR
+func vdiResourceSchema() map[string]schema.Attribute {
+	attributes := vdiSchema()
+	attributes["sr_uuid"] = schema.StringAttribute{
+		MarkdownDescription: "The UUID of the storage repository used." +
+			"\n\n-> **Note:** When `sr_uuid` is updated, the virtual disk image is migrated to the new storage repository by storage live migration if it's attached to a running VM, otherwise it's copied to the new storage repository and the VBDs are moved to the copy. The UUID of the virtual disk image is changed after the migration.",
+		Required: true,
+	}
+	attributes["source_vdi_uuid"] = schema.StringAttribute{
+		MarkdownDescription: "The UUID of the virtual disk image to clone or copy from, an empty virtual disk image is created when it's not set." +
+			"\n\n-> **Note:** The virtual disk image is replaced when `source_vdi_uuid` is updated.",
+		Optional: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["source_mode"] = schema.StringAttribute{
+		MarkdownDescription: "The way to create the virtual disk image from `source_vdi_uuid`, default to be `\"copy\"`." + "<br />" +
+			"`\"clone\"` - Clone the source virtual disk image in the same storage repository, it's fast if the storage repository supports copy-on-write.<br />" +
+			"`\"copy\"` - Make a full copy of the source virtual disk image in the storage repository of `sr_uuid`." +
+			"\n\n-> **Note:** The virtual disk image is replaced when `source_mode` is updated.",
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(vdiSourceModeCopy),
+		Validators: []validator.String{
+			stringvalidator.OneOf(vdiSourceModeClone, vdiSourceModeCopy),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["virtual_size"] = schema.Int64Attribute{
+		MarkdownDescription: "The size of virtual disk image (in bytes), it's required when `source_vdi_uuid` is not set, default to be the size of the source virtual disk image." +
+			"\n\n-> **Note:** `virtual_size` is not allowed to be updated.",
+		Optional: true,
+		Computed: true,
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.UseStateForUnknown(),
+		},
+	}
+	return attributes
+}
+
 func getVDICreateParams(ctx context.Context, session *xenapi.Session, data vdiResourceModel) (xenapi.VDIRecord, error) {
 	var record xenapi.VDIRecord
 	record.NameLabel = data.NameLabel.ValueString()
@@ -119,6 +197,9 @@ func getVDICreateParams(ctx context.Context, session *xenapi.Session, data vdiRe
 		return record, errors.New(err.Error())
 	}
 	record.SR = srRef
+	if data.VirtualSize.IsUnknown() || data.VirtualSize.IsNull() {
+		return record, errors.New(`"virtual_size" is required when "source_vdi_uuid" is not set`)
+	}
 	record.VirtualSize = int(data.VirtualSize.ValueInt64())
 	record.Type = xenapi.VdiType(data.Type.ValueString())
 	record.Sharable = data.Sharable.ValueBool()
@@ -140,6 +221,10 @@ func updateVDIResourceModel(ctx context.Context, session *xenapi.Session, record
 	}
 	data.SR = types.StringValue(srUUID)
 	data.VirtualSize = types.Int64Value(int64(record.VirtualSize))
+	// Set the default value when the resource is imported
+	if data.SourceMode.IsNull() {
+		data.SourceMode = types.StringValue(vdiSourceModeCopy)
+	}
 
 	return updateVDIResourceModelComputed(ctx, record, data)
 }
@@ -151,6 +236,7 @@ func updateVDIResourceModelComputed(ctx context.Context, record xenapi.VDIRecord
 	data.Type = types.StringValue(string(record.Type))
 	data.Sharable = types.BoolValue(record.Sharable)
 	data.ReadOnly = types.BoolValue(record.ReadOnly)
+	data.CbtEnabled = types.BoolValue(record.CbtEnabled)
 	var diags diag.Diagnostics
 	data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
 	if diags.HasError() {
@@ -160,10 +246,30 @@ func updateVDIResourceModelComputed(ctx context.Context, record xenapi.VDIRecord
 	return nil
 }
 
-func vdiResourceModelUpdateCheck(data vdiResourceModel, dataState vdiResourceModel) error {
-	if data.SR != dataState.SR {
-		return errors.New(`"sr_uuid" doesn't expected to be updated`)
+// setVDICbtEnabled enables or disables the changed block tracking of the VDI if it's changed
+func setVDICbtEnabled(session *xenapi.Session, ref xenapi.VDIRef, cbtEnabled types.Bool) error {
+	if cbtEnabled.IsUnknown() || cbtEnabled.IsNull() {
+		return nil
+	}
+	enabled, err := xenapi.VDI.GetCbtEnabled(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if enabled == cbtEnabled.ValueBool() {
+		return nil
+	}
+	if cbtEnabled.ValueBool() {
+		err = xenapi.VDI.EnableCbt(session, ref)
+	} else {
+		err = xenapi.VDI.DisableCbt(session, ref)
 	}
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func vdiResourceModelUpdateCheck(data vdiResourceModel, dataState vdiResourceModel) error {
 	if data.VirtualSize != dataState.VirtualSize {
 		return errors.New(`"virtual_size" doesn't expected to be updated`)
 	}
@@ -207,3 +313,311 @@ func cleanupVDIResource(session *xenapi.Session, ref xenapi.VDIRef) error {
 	}
 	return nil
 }
+
+// createVDIFromSource clones or copies the source VDI, and applies the configuration to the new VDI
+func createVDIFromSource(ctx context.Context, session *xenapi.Session, data vdiResourceModel) (xenapi.VDIRef, error) {
+	var vdiRef xenapi.VDIRef
+	sourceRef, err := xenapi.VDI.GetByUUID(session, data.SourceVDI.ValueString())
+	if err != nil {
+		return vdiRef, errors.New(err.Error())
+	}
+	sourceRecord, err := xenapi.VDI.GetRecord(session, sourceRef)
+	if err != nil {
+		return vdiRef, errors.New(err.Error())
+	}
+	srRef, err := xenapi.SR.GetByUUID(session, data.SR.ValueString())
+	if err != nil {
+		return vdiRef, errors.New(err.Error())
+	}
+	if string(sourceRecord.Type) != data.Type.ValueString() {
+		return vdiRef, fmt.Errorf("the type of the source VDI is %s, doesn't match %s", sourceRecord.Type, data.Type.ValueString())
+	}
+	virtualSize := sourceRecord.VirtualSize
+	if !data.VirtualSize.IsUnknown() && !data.VirtualSize.IsNull() {
+		if int(data.VirtualSize.ValueInt64()) < sourceRecord.VirtualSize {
+			return vdiRef, fmt.Errorf("\"virtual_size\" %d is less than the size %d of the source VDI", data.VirtualSize.ValueInt64(), sourceRecord.VirtualSize)
+		}
+		virtualSize = int(data.VirtualSize.ValueInt64())
+	}
+
+	if data.SourceMode.ValueString() == vdiSourceModeClone {
+		if sourceRecord.SR != srRef {
+			return vdiRef, errors.New("the source VDI can only be cloned in its own SR, set \"source_mode\" to \"copy\" to create the VDI in another SR")
+		}
+		vdiRef, err = xenapi.VDI.Clone(session, sourceRef, map[string]string{})
+	} else {
+		vdiRef, err = xenapi.VDI.Copy(session, sourceRef, srRef, "OpaqueRef:NULL", "OpaqueRef:NULL")
+	}
+	if err != nil {
+		return vdiRef, errors.New(err.Error())
+	}
+
+	err = setVDIFromSource(ctx, session, vdiRef, data, virtualSize != sourceRecord.VirtualSize, virtualSize)
+	if err != nil {
+		cleanupErr := cleanupVDIResource(session, vdiRef)
+		if cleanupErr != nil {
+			return vdiRef, errors.New(err.Error() + " " + cleanupErr.Error())
+		}
+		return vdiRef, err
+	}
+	return vdiRef, nil
+}
+
+func setVDIFromSource(ctx context.Context, session *xenapi.Session, vdiRef xenapi.VDIRef, data vdiResourceModel, resize bool, virtualSize int) error {
+	if resize {
+		err := xenapi.VDI.Resize(session, vdiRef, virtualSize)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	err := xenapi.VDI.SetSharable(session, vdiRef, data.Sharable.ValueBool())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.VDI.SetReadOnly(session, vdiRef, data.ReadOnly.ValueBool())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return vdiResourceModelUpdate(ctx, session, vdiRef, data)
+}
+
+// migrateVDI moves the VDI to the SR, VDI.pool_migrate is used when the VDI is attached to a running VM,
+// otherwise the VDI is copied to the SR and the VBDs are moved to the copy. The copy is attached to the VMs
+// before the VDI is detached, and it's rolled back when the VBDs can't be moved. It returns the new VDI.
+func migrateVDI(session *xenapi.Session, vdiRef xenapi.VDIRef, srUUID string) (xenapi.VDIRef, error) {
+	var newVDIRef xenapi.VDIRef
+	srRef, err := xenapi.SR.GetByUUID(session, srUUID)
+	if err != nil {
+		return newVDIRef, errors.New(err.Error())
+	}
+	vbdRefs, err := xenapi.VDI.GetVBDs(session, vdiRef)
+	if err != nil {
+		return newVDIRef, errors.New(err.Error())
+	}
+	var vbdRecords []xenapi.VBDRecord
+	for _, vbdRef := range vbdRefs {
+		vbdRecord, err := xenapi.VBD.GetRecord(session, vbdRef)
+		if err != nil {
+			return newVDIRef, errors.New(err.Error())
+		}
+		if vbdRecord.CurrentlyAttached {
+			newVDIRef, err = xenapi.VDI.PoolMigrate(session, vdiRef, srRef, map[string]string{})
+			if err != nil {
+				return newVDIRef, errors.New(err.Error())
+			}
+			return newVDIRef, nil
+		}
+		vbdRecords = append(vbdRecords, vbdRecord)
+	}
+
+	copyRef, err := xenapi.VDI.Copy(session, vdiRef, srRef, "OpaqueRef:NULL", "OpaqueRef:NULL")
+	if err != nil {
+		return newVDIRef, errors.New(err.Error())
+	}
+	// Attach the copy to the VMs before detaching the VDI, the VBD can't change its VDI and the device
+	// is still in use, so the new VBD is created on a free device and moved after the old VBD is destroyed
+	var newVBDRefs []xenapi.VBDRef
+	for _, vbdRecord := range vbdRecords {
+		newVBDRecord := vbdRecord
+		newVBDRecord.VDI = copyRef
+		newVBDRecord.Userdevice = "autodetect"
+		newVBDRef, err := xenapi.VBD.Create(session, newVBDRecord)
+		if err != nil {
+			return newVDIRef, rollbackVDIMigration(session, copyRef, newVBDRefs, nil, errors.New(err.Error()))
+		}
+		newVBDRefs = append(newVBDRefs, newVBDRef)
+	}
+	for i, vbdRef := range vbdRefs {
+		err = xenapi.VBD.Destroy(session, vbdRef)
+		if err != nil {
+			return newVDIRef, rollbackVDIMigration(session, copyRef, newVBDRefs, vbdRecords[:i], errors.New(err.Error()))
+		}
+	}
+	for i, newVBDRef := range newVBDRefs {
+		err = xenapi.VBD.SetUserdevice(session, newVBDRef, vbdRecords[i].Userdevice)
+		if err != nil {
+			return newVDIRef, rollbackVDIMigration(session, copyRef, newVBDRefs, vbdRecords, errors.New(err.Error()))
+		}
+	}
+	// The VMs use the copy from now on, return it even if the VDI can't be destroyed
+	err = cleanupVDIResource(session, vdiRef)
+	if err != nil {
+		return copyRef, err
+	}
+	return copyRef, nil
+}
+
+// rollbackVDIMigration destroys the copy of the VDI with its VBDs, and recreates the destroyed VBDs of the VDI
+func rollbackVDIMigration(session *xenapi.Session, copyRef xenapi.VDIRef, newVBDRefs []xenapi.VBDRef, destroyedVBDRecords []xenapi.VBDRecord, err error) error {
+	errMsg := err.Error()
+	for _, newVBDRef := range newVBDRefs {
+		errRollback := xenapi.VBD.Destroy(session, newVBDRef)
+		if errRollback != nil {
+			errMsg += "\n" + errRollback.Error()
+		}
+	}
+	errRollback := cleanupVDIResource(session, copyRef)
+	if errRollback != nil {
+		errMsg += "\n" + errRollback.Error()
+	}
+	for _, vbdRecord := range destroyedVBDRecords {
+		_, errRollback = xenapi.VBD.Create(session, vbdRecord)
+		if errRollback != nil {
+			errMsg += "\n" + errRollback.Error()
+		}
+	}
+	return errors.New(errMsg)
+}
+
+// vdiDataSourceModel describes the data source data model.
+type vdiDataSourceModel struct {
+	NameLabel   types.String    `tfsdk:"name_label"`
+	SR          types.String    `tfsdk:"sr_uuid"`
+	Type        types.String    `tfsdk:"type"`
+	IsASnapshot types.Bool      `tfsdk:"is_a_snapshot"`
+	Tags        types.List      `tfsdk:"tags"`
+	OtherConfig types.Map       `tfsdk:"other_config"`
+	VM          types.String    `tfsdk:"vm_uuid"`
+	Filter      []filterObject  `tfsdk:"filter"`
+	ExpectCount types.Int64     `tfsdk:"expect_count"`
+	DataItems   []vdiRecordData `tfsdk:"data_items"`
+}
+
+type vdiRecordData struct {
+	UUID                types.String `tfsdk:"uuid"`
+	NameLabel           types.String `tfsdk:"name_label"`
+	NameDescription     types.String `tfsdk:"name_description"`
+	SR                  types.String `tfsdk:"sr_uuid"`
+	VirtualSize         types.Int64  `tfsdk:"virtual_size"`
+	PhysicalUtilisation types.Int64  `tfsdk:"physical_utilisation"`
+	Type                types.String `tfsdk:"type"`
+	Sharable            types.Bool   `tfsdk:"sharable"`
+	ReadOnly            types.Bool   `tfsdk:"read_only"`
+	Managed             types.Bool   `tfsdk:"managed"`
+	Missing             types.Bool   `tfsdk:"missing"`
+	Location            types.String `tfsdk:"location"`
+	IsASnapshot         types.Bool   `tfsdk:"is_a_snapshot"`
+	SnapshotOf          types.String `tfsdk:"snapshot_of"`
+	Snapshots           types.List   `tfsdk:"snapshots"`
+	IsToolsIso          types.Bool   `tfsdk:"is_tools_iso"`
+	CbtEnabled          types.Bool   `tfsdk:"cbt_enabled"`
+	Tags                types.List   `tfsdk:"tags"`
+	OtherConfig         types.Map    `tfsdk:"other_config"`
+	SmConfig            types.Map    `tfsdk:"sm_config"`
+	VBDs                types.List   `tfsdk:"vbds"`
+	VMs                 types.List   `tfsdk:"vms"`
+}
+
+// matchVDIRecord checks the VDI record with the data source attributes which don't need extra API calls
+func matchVDIRecord(ctx context.Context, record xenapi.VDIRecord, data vdiDataSourceModel) (bool, error) {
+	if !data.NameLabel.IsNull() && record.NameLabel != data.NameLabel.ValueString() {
+		return false, nil
+	}
+	if !data.Type.IsNull() && string(record.Type) != data.Type.ValueString() {
+		return false, nil
+	}
+	if !data.IsASnapshot.IsNull() && record.IsASnapshot != data.IsASnapshot.ValueBool() {
+		return false, nil
+	}
+	if !data.Tags.IsNull() {
+		var tags []string
+		diags := data.Tags.ElementsAs(ctx, &tags, false)
+		if diags.HasError() {
+			return false, errors.New("unable to access VDI tags")
+		}
+		for _, tag := range tags {
+			if !slices.Contains(record.Tags, tag) {
+				return false, nil
+			}
+		}
+	}
+	if !data.OtherConfig.IsNull() {
+		otherConfig := make(map[string]string)
+		diags := data.OtherConfig.ElementsAs(ctx, &otherConfig, false)
+		if diags.HasError() {
+			return false, errors.New("unable to access VDI other config")
+		}
+		for key, value := range otherConfig {
+			recordValue, ok := record.OtherConfig[key]
+			if !ok || recordValue != value {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func updateVDIRecordData(ctx context.Context, session *xenapi.Session, record xenapi.VDIRecord, data *vdiRecordData) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.NameLabel = types.StringValue(record.NameLabel)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	srUUID, err := getUUIDFromSRRef(session, record.SR)
+	if err != nil {
+		return err
+	}
+	data.SR = types.StringValue(srUUID)
+	data.VirtualSize = types.Int64Value(int64(record.VirtualSize))
+	data.PhysicalUtilisation = types.Int64Value(int64(record.PhysicalUtilisation))
+	data.Type = types.StringValue(string(record.Type))
+	data.Sharable = types.BoolValue(record.Sharable)
+	data.ReadOnly = types.BoolValue(record.ReadOnly)
+	data.Managed = types.BoolValue(record.Managed)
+	data.Missing = types.BoolValue(record.Missing)
+	data.Location = types.StringValue(record.Location)
+	data.IsASnapshot = types.BoolValue(record.IsASnapshot)
+	snapshotOf, err := getUUIDFromVDIRef(session, record.SnapshotOf)
+	if err != nil {
+		return err
+	}
+	data.SnapshotOf = types.StringValue(snapshotOf)
+	snapshots, err := getVDIUUIDs(session, record.Snapshots)
+	if err != nil {
+		return err
+	}
+	var diags diag.Diagnostics
+	data.Snapshots, diags = types.ListValueFrom(ctx, types.StringType, snapshots)
+	if diags.HasError() {
+		return errors.New("unable to read VDI snapshots")
+	}
+	data.IsToolsIso = types.BoolValue(record.IsToolsIso)
+	data.CbtEnabled = types.BoolValue(record.CbtEnabled)
+	data.Tags, diags = types.ListValueFrom(ctx, types.StringType, record.Tags)
+	if diags.HasError() {
+		return errors.New("unable to read VDI tags")
+	}
+	data.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, record.OtherConfig)
+	if diags.HasError() {
+		return errors.New("unable to read VDI other config")
+	}
+	data.SmConfig, diags = types.MapValueFrom(ctx, types.StringType, record.SmConfig)
+	if diags.HasError() {
+		return errors.New("unable to read VDI SM config")
+	}
+	vbds, err := getVBDUUIDs(session, record.VBDs)
+	if err != nil {
+		return err
+	}
+	data.VBDs, diags = types.ListValueFrom(ctx, types.StringType, vbds)
+	if diags.HasError() {
+		return errors.New("unable to read VDI VBDs")
+	}
+	vms := []string{}
+	for _, vbdRef := range record.VBDs {
+		vmRef, err := xenapi.VBD.GetVM(session, vbdRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		vmUUID, err := getUUIDFromVMRef(session, vmRef)
+		if err != nil {
+			return err
+		}
+		if vmUUID != "" && !slices.Contains(vms, vmUUID) {
+			vms = append(vms, vmUUID)
+		}
+	}
+	data.VMs, diags = types.ListValueFrom(ctx, types.StringType, vms)
+	if diags.HasError() {
+		return errors.New("unable to read VDI VMs")
+	}
+	return nil
+}
@@ -3,12 +3,17 @@ package xenserver
 import (
 	"context"
 	"errors"
+	"net/netip"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"xenapi"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -19,25 +24,39 @@ import (
 )
 
 type vifResourceModel struct {
-	Network     types.String `tfsdk:"network_uuid"`
-	Device      types.String `tfsdk:"device"`
-	VIF         types.String `tfsdk:"vif_ref"`
-	MAC         types.String `tfsdk:"mac"`
-	OtherConfig types.Map    `tfsdk:"other_config"`
+	Network          types.String `tfsdk:"network_uuid"`
+	Device           types.String `tfsdk:"device"`
+	VIF              types.String `tfsdk:"vif_ref"`
+	MAC              types.String `tfsdk:"mac"`
+	OtherConfig      types.Map    `tfsdk:"other_config"`
+	LockingMode      types.String `tfsdk:"locking_mode"`
+	IPv4Allowed      types.Set    `tfsdk:"ipv4_allowed"`
+	IPv6Allowed      types.Set    `tfsdk:"ipv6_allowed"`
+	QosAlgorithmType types.String `tfsdk:"qos_algorithm_type"`
+	QosKbps          types.Int64  `tfsdk:"qos_kbps"`
+	MTU              types.Int32  `tfsdk:"mtu"`
 }
 
 var vifResourceModelAttrTypes = map[string]attr.Type{
-	"network_uuid": types.StringType,
-	"device":       types.StringType,
-	"vif_ref":      types.StringType,
-	"mac":          types.StringType,
-	"other_config": types.MapType{ElemType: types.StringType},
+	"network_uuid":       types.StringType,
+	"device":             types.StringType,
+	"vif_ref":            types.StringType,
+	"mac":                types.StringType,
+	"other_config":       types.MapType{ElemType: types.StringType},
+	"locking_mode":       types.StringType,
+	"ipv4_allowed":       types.SetType{ElemType: types.StringType},
+	"ipv6_allowed":       types.SetType{ElemType: types.StringType},
+	"qos_algorithm_type": types.StringType,
+	"qos_kbps":           types.Int64Type,
+	"mtu":                types.Int32Type,
 }
 
+const vifQosAlgorithmRatelimit = "ratelimit"
+
 func vifSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
 		"network_uuid": schema.StringAttribute{
-			MarkdownDescription: "Network UUID to attach to VIF.",
+			MarkdownDescription: "Network UUID to attach to VIF, the SR-IOV network created by `xenserver_network_sriov` is also supported.",
 			Required:            true,
 		},
 		"device": schema.StringAttribute{
@@ -68,10 +87,71 @@ func vifSchema() map[string]schema.Attribute {
 			},
 		},
 		"other_config": schema.MapAttribute{
-			MarkdownDescription: "The additional configuration of the network interface, default to be `{}`.Find more details in [advanced-settings-for-network-interfaces](https://docs.xenserver.com/en-us/xenserver/developer/sdk-guide/xs-api-extensions#advanced-settings-for-network-interfaces).",
+			MarkdownDescription: "The additional configuration of the network interface, default to be `{}`.Find more details in [advanced-settings-for-network-interfaces](https://docs.xenserver.com/en-us/xenserver/developer/sdk-guide/xs-api-extensions#advanced-settings-for-network-interfaces)." + "<br />" +
+				"The `\"mtu\"` key is deprecated, use `mtu` instead.",
+			ElementType: types.StringType,
+			Optional:    true,
+			Computed:    true,
+			Validators: []validator.Map{
+				vifOtherConfigMTUValidator{},
+			},
+		},
+		"locking_mode": schema.StringAttribute{
+			MarkdownDescription: "The locking mode of the VIF, default to be `\"network_default\"`." + "<br />" +
+				"This value can be one of [`\"network_default\", \"locked\", \"unlocked\", \"disabled\"`]. When set to `\"locked\"`, the VIF can only send traffic from the MAC address and the IP addresses in `ipv4_allowed` and `ipv6_allowed`.",
+			Optional: true,
+			Computed: true,
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					string(xenapi.VifLockingModeNetworkDefault),
+					string(xenapi.VifLockingModeLocked),
+					string(xenapi.VifLockingModeUnlocked),
+					string(xenapi.VifLockingModeDisabled),
+				),
+			},
+		},
+		"ipv4_allowed": schema.SetAttribute{
+			MarkdownDescription: "The IPv4 addresses which can be used by the VIF when `locking_mode` is `\"locked\"`, default to be `[]`.",
 			ElementType:         types.StringType,
 			Optional:            true,
 			Computed:            true,
+			Validators: []validator.Set{
+				setvalidator.ValueStringsAre(ipv4AddressValidator{}),
+			},
+		},
+		"ipv6_allowed": schema.SetAttribute{
+			MarkdownDescription: "The IPv6 addresses which can be used by the VIF when `locking_mode` is `\"locked\"`, default to be `[]`.",
+			ElementType:         types.StringType,
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.Set{
+				setvalidator.ValueStringsAre(ipv6AddressValidator{}),
+			},
+		},
+		"qos_algorithm_type": schema.StringAttribute{
+			MarkdownDescription: "The QoS algorithm of the VIF, default to be `\"\"`." + "<br />" +
+				"Set it as `\"ratelimit\"` to limit the bandwidth of the VIF to `qos_kbps`.",
+			Optional: true,
+			Computed: true,
+			Validators: []validator.String{
+				stringvalidator.OneOf("", vifQosAlgorithmRatelimit),
+			},
+		},
+		"qos_kbps": schema.Int64Attribute{
+			MarkdownDescription: "The bandwidth limit of the VIF in kilobytes per second, default to be `0`. It only works when `qos_algorithm_type` is `\"ratelimit\"`.",
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+		"mtu": schema.Int32Attribute{
+			MarkdownDescription: "The MTU of the VIF in octets, default inherited from the network.",
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.Int32{
+				int32validator.Between(68, 65535),
+			},
 		},
 	}
 }
@@ -89,6 +169,286 @@ func setVIFDefaults(ctx context.Context, vif *vifResourceModel) {
 			tflog.Debug(ctx, "unable to set VIF other config")
 		}
 	}
+
+	if vif.LockingMode.IsUnknown() {
+		vif.LockingMode = types.StringValue(string(xenapi.VifLockingModeNetworkDefault))
+	}
+
+	if vif.IPv4Allowed.IsUnknown() {
+		vif.IPv4Allowed = types.SetValueMust(types.StringType, []attr.Value{})
+	}
+
+	if vif.IPv6Allowed.IsUnknown() {
+		vif.IPv6Allowed = types.SetValueMust(types.StringType, []attr.Value{})
+	}
+
+	if vif.QosAlgorithmType.IsUnknown() {
+		vif.QosAlgorithmType = types.StringValue("")
+	}
+
+	if vif.QosKbps.IsUnknown() {
+		vif.QosKbps = types.Int64Value(0)
+	}
+
+	// map the deprecated "mtu" key in other config onto mtu, otherwise leave mtu unknown, it's inherited from the network
+	if vif.MTU.IsUnknown() || vif.MTU.IsNull() {
+		if value, ok := vif.OtherConfig.Elements()["mtu"].(types.String); ok {
+			mtu, err := strconv.Atoi(value.ValueString())
+			if err == nil {
+				mtuInt32, err := ToInt32(mtu)
+				if err == nil {
+					vif.MTU = types.Int32Value(mtuInt32)
+				}
+			}
+		}
+	}
+}
+
+// vifOtherConfigMTUValidator accepts the deprecated "mtu" key in the VIF other config with a warning
+type vifOtherConfigMTUValidator struct{}
+
+func (v vifOtherConfigMTUValidator) Description(_ context.Context) string {
+	return `the "mtu" key is deprecated, use mtu instead`
+}
+
+func (v vifOtherConfigMTUValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v vifOtherConfigMTUValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value, ok := req.ConfigValue.Elements()["mtu"].(types.String)
+	if !ok || value.IsUnknown() {
+		return
+	}
+	mtu, err := strconv.Atoi(value.ValueString())
+	if err != nil || mtu < 68 || mtu > 65535 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid VIF MTU",
+			`The "mtu" key in other_config should be an integer between 68 and 65535, got: `+value.ValueString(),
+		)
+		return
+	}
+	resp.Diagnostics.AddAttributeWarning(
+		req.Path,
+		"Deprecated VIF other_config key",
+		`The "mtu" key in other_config is deprecated and will be removed in a future release, use mtu of the network interface instead.`,
+	)
+}
+
+// ipv4AddressValidator checks the value is a valid IPv4 address
+type ipv4AddressValidator struct{}
+
+func (v ipv4AddressValidator) Description(_ context.Context) string {
+	return "value must be a valid IPv4 address"
+}
+
+func (v ipv4AddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipv4AddressValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	addr, err := netip.ParseAddr(req.ConfigValue.ValueString())
+	if err != nil || !addr.Is4() {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid IPv4 address",
+			"Input is not a valid IPv4 address: "+req.ConfigValue.ValueString(),
+		)
+	}
+}
+
+// ipv6AddressValidator checks the value is a valid IPv6 address
+type ipv6AddressValidator struct{}
+
+func (v ipv6AddressValidator) Description(_ context.Context) string {
+	return "value must be a valid IPv6 address"
+}
+
+func (v ipv6AddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipv6AddressValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	addr, err := netip.ParseAddr(req.ConfigValue.ValueString())
+	if err != nil || !addr.Is6() || addr.Is4In6() || addr.Zone() != "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid IPv6 address",
+			"Input is not a valid IPv6 address: "+req.ConfigValue.ValueString(),
+		)
+	}
+}
+
+func getVIFQosAlgorithmParams(vif vifResourceModel) map[string]string {
+	qosParams := make(map[string]string)
+	if vif.QosAlgorithmType.ValueString() == vifQosAlgorithmRatelimit {
+		qosParams["kbps"] = strconv.FormatInt(vif.QosKbps.ValueInt64(), 10)
+	}
+	return qosParams
+}
+
+// getVIFOtherConfig returns the other config from the plan with the "mtu" key, from XAPI code,
+// the mtu is actually works when set in vif.other_config instead of vif.MTU
+func getVIFOtherConfig(ctx context.Context, vif vifResourceModel) (map[string]string, error) {
+	otherConfig := make(map[string]string)
+	diags := vif.OtherConfig.ElementsAs(ctx, &otherConfig, false)
+	if diags.HasError() {
+		return nil, errors.New("unable to get VIF other config")
+	}
+	if !vif.MTU.IsUnknown() && !vif.MTU.IsNull() {
+		mtu := strconv.Itoa(int(vif.MTU.ValueInt32()))
+		if value, ok := otherConfig["mtu"]; ok && value != mtu {
+			return nil, errors.New(`"network_interface.mtu" and the deprecated "mtu" key in "network_interface.other_config" should not be set to different values`)
+		}
+		otherConfig["mtu"] = mtu
+	}
+	return otherConfig, nil
+}
+
+// updateVIFModelFromRecord reads the VIF settings which are managed by the provider from the VIF record,
+// the "mtu" key is kept in other config only if the deprecated key is still used
+func updateVIFModelFromRecord(ctx context.Context, vifRecord xenapi.VIFRecord, vif *vifResourceModel, keepMTUKey bool) error {
+	var diags diag.Diagnostics
+	otherConfig := make(map[string]string)
+	for key, value := range vifRecord.OtherConfig {
+		if key != "mtu" || keepMTUKey {
+			otherConfig[key] = value
+		}
+	}
+	vif.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, otherConfig)
+	if diags.HasError() {
+		return errors.New("unable to read VIF other config")
+	}
+
+	vif.LockingMode = types.StringValue(string(vifRecord.LockingMode))
+	vif.IPv4Allowed, diags = types.SetValueFrom(ctx, types.StringType, append([]string{}, vifRecord.Ipv4Allowed...))
+	if diags.HasError() {
+		return errors.New("unable to read VIF IPv4 allowed")
+	}
+	vif.IPv6Allowed, diags = types.SetValueFrom(ctx, types.StringType, append([]string{}, vifRecord.Ipv6Allowed...))
+	if diags.HasError() {
+		return errors.New("unable to read VIF IPv6 allowed")
+	}
+
+	vif.QosAlgorithmType = types.StringValue(vifRecord.QosAlgorithmType)
+	vif.QosKbps = types.Int64Value(0)
+	if kbps, ok := vifRecord.QosAlgorithmParams["kbps"]; ok {
+		kbpsInt, err := strconv.ParseInt(kbps, 10, 64)
+		if err != nil {
+			return errors.New("unable to convert qos kbps to an int value")
+		}
+		vif.QosKbps = types.Int64Value(kbpsInt)
+	}
+
+	mtu := vifRecord.MTU
+	if value, ok := vifRecord.OtherConfig["mtu"]; ok {
+		mtuInt, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("unable to convert VIF mtu to an int value")
+		}
+		mtu = mtuInt
+	}
+	mtuInt32, err := ToInt32(mtu)
+	if err != nil {
+		return err
+	}
+	vif.MTU = types.Int32Value(mtuInt32)
+
+	return nil
+}
+
+// updateVIFAllowedIPs adds the planned IPs which are not allowed yet and removes the ones not in the plan
+func updateVIFAllowedIPs(ctx context.Context, session *xenapi.Session, vifRef xenapi.VIFRef, plan types.Set, state types.Set, ipv6 bool) error {
+	planIPs := []string{}
+	diags := plan.ElementsAs(ctx, &planIPs, false)
+	if diags.HasError() {
+		return errors.New("unable to get VIF allowed IPs in plan data")
+	}
+	stateIPs := []string{}
+	if !state.IsUnknown() && !state.IsNull() {
+		diags = state.ElementsAs(ctx, &stateIPs, false)
+		if diags.HasError() {
+			return errors.New("unable to get VIF allowed IPs in state data")
+		}
+	}
+
+	addAllowedIP, removeAllowedIP := xenapi.VIF.AddIpv4Allowed, xenapi.VIF.RemoveIpv4Allowed
+	if ipv6 {
+		addAllowedIP, removeAllowedIP = xenapi.VIF.AddIpv6Allowed, xenapi.VIF.RemoveIpv6Allowed
+	}
+	for _, ip := range stateIPs {
+		if !slices.Contains(planIPs, ip) {
+			err := removeAllowedIP(session, vifRef, ip)
+			if err != nil {
+				return errors.New(err.Error())
+			}
+		}
+	}
+	for _, ip := range planIPs {
+		if !slices.Contains(stateIPs, ip) {
+			err := addAllowedIP(session, vifRef, ip)
+			if err != nil {
+				return errors.New(err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// updateVIFSettings updates the settings of an existing VIF in place
+func updateVIFSettings(ctx context.Context, session *xenapi.Session, plan vifResourceModel, state vifResourceModel) error {
+	vifRef := xenapi.VIFRef(state.VIF.ValueString())
+
+	if !plan.OtherConfig.Equal(state.OtherConfig) || (!plan.MTU.IsUnknown() && !plan.MTU.Equal(state.MTU)) {
+		otherConfig, err := getVIFOtherConfig(ctx, plan)
+		if err != nil {
+			return err
+		}
+		err = xenapi.VIF.SetOtherConfig(session, vifRef, otherConfig)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	// the allowed IPs should be set before locking the VIF
+	err := updateVIFAllowedIPs(ctx, session, vifRef, plan.IPv4Allowed, state.IPv4Allowed, false)
+	if err != nil {
+		return err
+	}
+	err = updateVIFAllowedIPs(ctx, session, vifRef, plan.IPv6Allowed, state.IPv6Allowed, true)
+	if err != nil {
+		return err
+	}
+
+	if !plan.LockingMode.Equal(state.LockingMode) {
+		err = xenapi.VIF.SetLockingMode(session, vifRef, xenapi.VifLockingMode(plan.LockingMode.ValueString()))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	if !plan.QosAlgorithmType.Equal(state.QosAlgorithmType) || !plan.QosKbps.Equal(state.QosKbps) {
+		err = xenapi.VIF.SetQosAlgorithmType(session, vifRef, plan.QosAlgorithmType.ValueString())
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		err = xenapi.VIF.SetQosAlgorithmParams(session, vifRef, getVIFQosAlgorithmParams(plan))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	return nil
 }
 
 func createVIF(ctx context.Context, vif vifResourceModel, vmRef xenapi.VMRef, session *xenapi.Session) error {
@@ -100,22 +460,44 @@ func createVIF(ctx context.Context, vif vifResourceModel, vmRef xenapi.VMRef, se
 
 	setVIFDefaults(ctx, &vif)
 
-	otherConfig := make(map[string]string)
-	diags := vif.OtherConfig.ElementsAs(ctx, &otherConfig, false)
+	otherConfig, err := getVIFOtherConfig(ctx, vif)
+	if err != nil {
+		return err
+	}
+
+	// inherit the mtu from the network if it's not set
+	mtu := int(vif.MTU.ValueInt32())
+	if vif.MTU.IsUnknown() || vif.MTU.IsNull() {
+		mtu, err = xenapi.Network.GetMTU(session, networkRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	ipv4Allowed := []string{}
+	diags := vif.IPv4Allowed.ElementsAs(ctx, &ipv4Allowed, false)
+	if diags.HasError() {
+		return errors.New("unable to get VIF IPv4 allowed")
+	}
+	ipv6Allowed := []string{}
+	diags = vif.IPv6Allowed.ElementsAs(ctx, &ipv6Allowed, false)
 	if diags.HasError() {
-		return errors.New("unable to get VIF other config")
+		return errors.New("unable to get VIF IPv6 allowed")
 	}
 
 	vifRecord := xenapi.VIFRecord{
-		VM:      vmRef,
-		Network: networkRef,
-		Device:  vif.Device.ValueString(),
-		MAC:     vif.MAC.ValueString(),
-		// from XAPI code, the mtu is actually works when set in vif.other_config instead of vif.MTU, give it a default value here
-		MTU:              1500,
-		OtherConfig:      otherConfig,
-		LockingMode:      xenapi.VifLockingModeNetworkDefault,
-		MACAutogenerated: vif.MAC.ValueString() == "",
+		VM:                 vmRef,
+		Network:            networkRef,
+		Device:             vif.Device.ValueString(),
+		MAC:                vif.MAC.ValueString(),
+		MTU:                mtu,
+		OtherConfig:        otherConfig,
+		LockingMode:        xenapi.VifLockingMode(vif.LockingMode.ValueString()),
+		Ipv4Allowed:        ipv4Allowed,
+		Ipv6Allowed:        ipv6Allowed,
+		QosAlgorithmType:   vif.QosAlgorithmType.ValueString(),
+		QosAlgorithmParams: getVIFQosAlgorithmParams(vif),
+		MACAutogenerated:   vif.MAC.ValueString() == "",
 	}
 
 	vifRef, err = xenapi.VIF.Create(session, vifRecord)
@@ -248,17 +630,9 @@ func updateVIFs(ctx context.Context, plan vmResourceModel, state vmResourceModel
 				return err
 			}
 
-			if !planVIF.OtherConfig.Equal(stateVIF.OtherConfig) {
-				otherConfig := make(map[string]string)
-				diags := planVIF.OtherConfig.ElementsAs(ctx, &otherConfig, false)
-				if diags.HasError() {
-					return errors.New("unable to get network_interface.other_config")
-				}
-
-				err = xenapi.VIF.SetOtherConfig(session, xenapi.VIFRef(stateVIF.VIF.ValueString()), otherConfig)
-				if err != nil {
-					return errors.New(err.Error())
-				}
+			err = updateVIFSettings(ctx, session, planVIF, stateVIF)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -0,0 +1,320 @@
+package xenserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"xenapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &vmApplianceResource{}
+	_ resource.ResourceWithConfigure   = &vmApplianceResource{}
+	_ resource.ResourceWithImportState = &vmApplianceResource{}
+)
+
+func NewVMApplianceResource() resource.Resource {
+	return &vmApplianceResource{}
+}
+
+// vmApplianceResource defines the resource implementation.
+type vmApplianceResource struct {
+	session *xenapi.Session
+}
+
+func (r *vmApplianceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_appliance"
+}
+
+func (r *vmApplianceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Provides a VM appliance (vApp) resource, a group of VMs which are started and shut down in order.",
+		Attributes: map[string]schema.Attribute{
+			"name_label": schema.StringAttribute{
+				MarkdownDescription: "The name of the VM appliance.",
+				Required:            true,
+			},
+			"name_description": schema.StringAttribute{
+				MarkdownDescription: "The description of the VM appliance, default to be `\"\"`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"vms": schema.SetNestedAttribute{
+				MarkdownDescription: "A set of VMs which belong to the VM appliance, default to be `[]`." + "<br />" +
+					"VMs not in this set will be removed from the VM appliance.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"vm_uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the VM.",
+							Required:            true,
+						},
+						"order": schema.Int32Attribute{
+							MarkdownDescription: "The order in which the VM is started or shut down in the VM appliance, default to be `0`." + "<br />" +
+								"VMs with a lower order are started first and shut down last.",
+							Optional: true,
+							Computed: true,
+							Validators: []validator.Int32{
+								int32validator.AtLeast(0),
+							},
+						},
+						"start_delay": schema.Int32Attribute{
+							MarkdownDescription: "The delay (in seconds) to wait after starting the VM before starting the next order group, default to be `0`.",
+							Optional:            true,
+							Computed:            true,
+							Validators: []validator.Int32{
+								int32validator.AtLeast(0),
+							},
+						},
+						"shutdown_delay": schema.Int32Attribute{
+							MarkdownDescription: "The delay (in seconds) to wait after shutting down the VM before shutting down the next order group, default to be `0`.",
+							Optional:            true,
+							Computed:            true,
+							Validators: []validator.Int32{
+								int32validator.AtLeast(0),
+							},
+						},
+					},
+				},
+				Optional: true,
+				Computed: true,
+				Default:  setdefault.StaticValue(types.SetValueMust(types.ObjectType{AttrTypes: vmApplianceVMModelAttrTypes}, []attr.Value{})),
+			},
+			"power_state": schema.StringAttribute{
+				MarkdownDescription: "The power state of the VMs in the VM appliance." + "<br />" +
+					"Can be set as `\"running\"` or `\"halted\"`. When set, the VM appliance is started or cleanly shut down, the VMs are handled in groups by `order` and `start_delay` or `shutdown_delay` is waited between groups. Paused VMs are unpaused first, suspended VMs are resumed before starting or hard shut down before halting." + "<br />" +
+					"If not set, the power state of the VMs is not managed. The state is reported as `\"mixed\"` when only part of the VMs are running.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(vmAppliancePowerStateRunning, vmAppliancePowerStateHalted),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the VM appliance.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The test ID of the VM appliance.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Set the parameter of the resource, pass value from provider
+func (r *vmApplianceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*xsProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *xenserver.xsProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.session = providerData.session
+}
+
+func (r *vmApplianceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data vmApplianceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating VM appliance...")
+	record := xenapi.VMApplianceRecord{
+		NameLabel:       data.NameLabel.ValueString(),
+		NameDescription: data.NameDescription.ValueString(),
+	}
+	applianceRef, err := xenapi.VMAppliance.Create(r.session, record)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create VM appliance",
+			err.Error(),
+		)
+		return
+	}
+	err = vmApplianceResourceModelUpdate(ctx, r.session, applianceRef, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to set VM appliance",
+			err.Error(),
+		)
+		err = cleanupVMApplianceResource(r.session, applianceRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VM appliance resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	applianceRecord, err := xenapi.VMAppliance.GetRecord(r.session, applianceRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VM appliance record",
+			err.Error(),
+		)
+		err = cleanupVMApplianceResource(r.session, applianceRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VM appliance resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	err = updateVMApplianceResourceModel(ctx, r.session, applianceRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of VMApplianceResourceModel",
+			err.Error(),
+		)
+		err = cleanupVMApplianceResource(r.session, applianceRef)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error cleaning up VM appliance resource",
+				err.Error(),
+			)
+		}
+		return
+	}
+	tflog.Debug(ctx, "VM appliance created")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *vmApplianceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data vmApplianceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite data with refreshed resource state
+	applianceRef, err := xenapi.VMAppliance.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VM appliance ref",
+			err.Error(),
+		)
+		return
+	}
+	applianceRecord, err := xenapi.VMAppliance.GetRecord(r.session, applianceRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VM appliance record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateVMApplianceResourceModel(ctx, r.session, applianceRecord, &data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of VMApplianceResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *vmApplianceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan vmApplianceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applianceRef, err := xenapi.VMAppliance.GetByUUID(r.session, plan.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VM appliance ref",
+			err.Error(),
+		)
+		return
+	}
+	err = vmApplianceResourceModelUpdate(ctx, r.session, applianceRef, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update VM appliance resource",
+			err.Error(),
+		)
+		return
+	}
+	applianceRecord, err := xenapi.VMAppliance.GetRecord(r.session, applianceRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VM appliance record",
+			err.Error(),
+		)
+		return
+	}
+	err = updateVMApplianceResourceModel(ctx, r.session, applianceRecord, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update the fields of VMApplianceResourceModel",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vmApplianceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data vmApplianceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting VM appliance...")
+	applianceRef, err := xenapi.VMAppliance.GetByUUID(r.session, data.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get VM appliance ref",
+			err.Error(),
+		)
+		return
+	}
+	err = cleanupVMApplianceResource(r.session, applianceRef)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete VM appliance",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "VM appliance deleted")
+}
+
+func (r *vmApplianceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
+}
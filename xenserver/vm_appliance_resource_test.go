@@ -0,0 +1,125 @@
+package xenserver
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccVMApplianceResourceConfig(name_label string, extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_network" "network" {}
+
+resource "xenserver_vm" "db" {
+	name_label     = "A test appliance db VM"
+	template_name  = "Windows 11"
+	static_mem_max = 4 * 1024 * 1024 * 1024
+	vcpus          = 2
+	network_interface = [
+		{
+		device       = "0"
+		network_uuid = data.xenserver_network.network.data_items[0].uuid,
+		},
+	]
+}
+
+resource "xenserver_vm" "web" {
+	name_label     = "A test appliance web VM"
+	template_name  = "Windows 11"
+	static_mem_max = 4 * 1024 * 1024 * 1024
+	vcpus          = 2
+	network_interface = [
+		{
+		device       = "0"
+		network_uuid = data.xenserver_network.network.data_items[0].uuid,
+		},
+	]
+}
+
+resource "xenserver_vm_appliance" "test_appliance" {
+	name_label = "%s"
+	%s
+}
+`, name_label, extra_config)
+}
+
+func TestAccVMApplianceResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Testing with expected failure
+			{
+				Config:      providerConfig + testAccVMApplianceResourceConfig("Test appliance A", `power_state = "paused"`),
+				ExpectError: regexp.MustCompile(`power_state value must be one of: \["running" "halted"\]`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMApplianceResourceConfig("Test appliance A", `
+	vms = [
+		{
+			vm_uuid     = xenserver_vm.db.uuid
+			order       = 0
+			start_delay = 10
+		},
+	]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "name_label", "Test appliance A"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "name_description", ""),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.0.order", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.0.start_delay", "10"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.0.shutdown_delay", "0"),
+					resource.TestCheckResourceAttrSet("xenserver_vm_appliance.test_appliance", "uuid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_vm_appliance.test_appliance",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccVMApplianceResourceConfig("Test appliance B", `
+	name_description = "A test appliance"
+	vms = [
+		{
+			vm_uuid     = xenserver_vm.db.uuid
+			order       = 0
+			start_delay = 10
+		},
+		{
+			vm_uuid        = xenserver_vm.web.uuid
+			order          = 1
+			shutdown_delay = 5
+		},
+	]
+	power_state = "halted"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "name_label", "Test appliance B"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "name_description", "A test appliance"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.#", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "power_state", "halted"),
+				),
+			},
+			// Remove VM from appliance
+			{
+				Config: providerConfig + testAccVMApplianceResourceConfig("Test appliance B", `
+	vms = [
+		{
+			vm_uuid = xenserver_vm.web.uuid
+			order   = 1
+		},
+	]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm_appliance.test_appliance", "vms.0.order", "1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,318 @@
+package xenserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"xenapi"
+)
+
+type vmApplianceResourceModel struct {
+	NameLabel       types.String `tfsdk:"name_label"`
+	NameDescription types.String `tfsdk:"name_description"`
+	VMs             types.Set    `tfsdk:"vms"`
+	PowerState      types.String `tfsdk:"power_state"`
+	UUID            types.String `tfsdk:"uuid"`
+	ID              types.String `tfsdk:"id"`
+}
+
+type vmApplianceVMModel struct {
+	VM            types.String `tfsdk:"vm_uuid"`
+	Order         types.Int32  `tfsdk:"order"`
+	StartDelay    types.Int32  `tfsdk:"start_delay"`
+	ShutdownDelay types.Int32  `tfsdk:"shutdown_delay"`
+}
+
+var vmApplianceVMModelAttrTypes = map[string]attr.Type{
+	"vm_uuid":        types.StringType,
+	"order":          types.Int32Type,
+	"start_delay":    types.Int32Type,
+	"shutdown_delay": types.Int32Type,
+}
+
+const (
+	vmAppliancePowerStateRunning = "running"
+	vmAppliancePowerStateHalted  = "halted"
+	vmAppliancePowerStateMixed   = "mixed"
+)
+
+func setVMApplianceVMDefaults(vm *vmApplianceVMModel) {
+	// Work around for https://github.com/hashicorp/terraform-plugin-framework/issues/726
+	if vm.Order.IsUnknown() || vm.Order.IsNull() {
+		vm.Order = types.Int32Value(0)
+	}
+	if vm.StartDelay.IsUnknown() || vm.StartDelay.IsNull() {
+		vm.StartDelay = types.Int32Value(0)
+	}
+	if vm.ShutdownDelay.IsUnknown() || vm.ShutdownDelay.IsNull() {
+		vm.ShutdownDelay = types.Int32Value(0)
+	}
+}
+
+func getVMApplianceVMsFromPlan(ctx context.Context, data vmApplianceResourceModel) (map[string]vmApplianceVMModel, error) {
+	elements := make([]vmApplianceVMModel, 0, len(data.VMs.Elements()))
+	diags := data.VMs.ElementsAs(ctx, &elements, false)
+	if diags.HasError() {
+		return nil, errors.New("unable to get vms elements")
+	}
+	vms := make(map[string]vmApplianceVMModel)
+	for _, vm := range elements {
+		setVMApplianceVMDefaults(&vm)
+		if _, ok := vms[vm.VM.ValueString()]; ok {
+			return nil, errors.New("duplicate vm_uuid " + vm.VM.ValueString() + " in vms")
+		}
+		vms[vm.VM.ValueString()] = vm
+	}
+	return vms, nil
+}
+
+// getVMAppliancePowerState returns "running" if all the VMs in the appliance are running,
+// "halted" if all of them are halted, otherwise "mixed".
+func getVMAppliancePowerState(session *xenapi.Session, vmRefs []xenapi.VMRef) (string, error) {
+	running, halted := 0, 0
+	for _, vmRef := range vmRefs {
+		powerState, err := xenapi.VM.GetPowerState(session, vmRef)
+		if err != nil {
+			return "", errors.New(err.Error())
+		}
+		switch powerState {
+		case xenapi.VMPowerStateRunning:
+			running++
+		case xenapi.VMPowerStateHalted:
+			halted++
+		case xenapi.VMPowerStatePaused, xenapi.VMPowerStateSuspended, xenapi.VMPowerStateUnrecognized:
+		}
+	}
+	if running == len(vmRefs) {
+		return vmAppliancePowerStateRunning, nil
+	}
+	if halted == len(vmRefs) {
+		return vmAppliancePowerStateHalted, nil
+	}
+	return vmAppliancePowerStateMixed, nil
+}
+
+func updateVMApplianceResourceModel(ctx context.Context, session *xenapi.Session, record xenapi.VMApplianceRecord, data *vmApplianceResourceModel) error {
+	data.NameLabel = types.StringValue(record.NameLabel)
+
+	var vmList []vmApplianceVMModel
+	for _, vmRef := range record.VMs {
+		vmRecord, err := xenapi.VM.GetRecord(session, vmRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		order, err := ToInt32(vmRecord.Order)
+		if err != nil {
+			return err
+		}
+		startDelay, err := ToInt32(vmRecord.StartDelay)
+		if err != nil {
+			return err
+		}
+		shutdownDelay, err := ToInt32(vmRecord.ShutdownDelay)
+		if err != nil {
+			return err
+		}
+		vmList = append(vmList, vmApplianceVMModel{
+			VM:            types.StringValue(vmRecord.UUID),
+			Order:         types.Int32Value(order),
+			StartDelay:    types.Int32Value(startDelay),
+			ShutdownDelay: types.Int32Value(shutdownDelay),
+		})
+	}
+	vms, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: vmApplianceVMModelAttrTypes}, vmList)
+	if diags.HasError() {
+		return errors.New("unable to update data for vm_appliance vms")
+	}
+	data.VMs = vms
+
+	// only refresh power_state when it is managed by terraform
+	if !data.PowerState.IsNull() && len(record.VMs) > 0 {
+		powerState, err := getVMAppliancePowerState(session, record.VMs)
+		if err != nil {
+			return err
+		}
+		data.PowerState = types.StringValue(powerState)
+	}
+
+	return updateVMApplianceResourceModelComputed(record, data)
+}
+
+func updateVMApplianceResourceModelComputed(record xenapi.VMApplianceRecord, data *vmApplianceResourceModel) error {
+	data.UUID = types.StringValue(record.UUID)
+	data.ID = types.StringValue(record.UUID)
+	data.NameDescription = types.StringValue(record.NameDescription)
+	return nil
+}
+
+func setVMApplianceVM(session *xenapi.Session, ref xenapi.VMApplianceRef, vm vmApplianceVMModel) error {
+	vmRef, err := xenapi.VM.GetByUUID(session, vm.VM.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.VM.SetAppliance(session, vmRef, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.VM.SetOrder(session, vmRef, int(vm.Order.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.VM.SetStartDelay(session, vmRef, int(vm.StartDelay.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.VM.SetShutdownDelay(session, vmRef, int(vm.ShutdownDelay.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func removeVMApplianceVM(session *xenapi.Session, vmRef xenapi.VMRef) error {
+	err := xenapi.VM.SetAppliance(session, vmRef, xenapi.VMApplianceRef("OpaqueRef:NULL"))
+	if err != nil && !strings.Contains(err.Error(), "HANDLE_INVALID") {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// updateVMApplianceVMs makes the appliance membership match the plan, VMs not in the plan are
+// removed from the appliance and the order and delays of the others are set as planned.
+func updateVMApplianceVMs(ctx context.Context, session *xenapi.Session, ref xenapi.VMApplianceRef, data vmApplianceResourceModel) error {
+	planVMs, err := getVMApplianceVMsFromPlan(ctx, data)
+	if err != nil {
+		return err
+	}
+	vmRefs, err := xenapi.VMAppliance.GetVMs(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	for _, vmRef := range vmRefs {
+		vmUUID, err := xenapi.VM.GetUUID(session, vmRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		if _, ok := planVMs[vmUUID]; !ok {
+			err = removeVMApplianceVM(session, vmRef)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, vm := range planVMs {
+		err = setVMApplianceVM(session, ref, vm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateVMAppliancePowerState starts or shuts down the appliance, XAPI handles the VMs in
+// groups by their "order" and waits "start_delay" or "shutdown_delay" between them.
+func updateVMAppliancePowerState(session *xenapi.Session, ref xenapi.VMApplianceRef, data vmApplianceResourceModel) error {
+	if data.PowerState.IsNull() || data.PowerState.IsUnknown() {
+		return nil
+	}
+	vmRefs, err := xenapi.VMAppliance.GetVMs(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if len(vmRefs) == 0 {
+		return nil
+	}
+	powerState, err := getVMAppliancePowerState(session, vmRefs)
+	if err != nil {
+		return err
+	}
+	if powerState == data.PowerState.ValueString() {
+		return nil
+	}
+	err = prepareVMApplianceVMsForPowerState(session, vmRefs, data.PowerState.ValueString())
+	if err != nil {
+		return err
+	}
+	powerState, err = getVMAppliancePowerState(session, vmRefs)
+	if err != nil {
+		return err
+	}
+	if powerState == data.PowerState.ValueString() {
+		return nil
+	}
+	switch data.PowerState.ValueString() {
+	case vmAppliancePowerStateRunning:
+		err = xenapi.VMAppliance.Start(session, ref, false)
+	case vmAppliancePowerStateHalted:
+		err = xenapi.VMAppliance.CleanShutdown(session, ref)
+	}
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// prepareVMApplianceVMsForPowerState handles the paused and suspended VMs in the appliance,
+// as the appliance can only be started or cleanly shut down when its VMs are running or halted.
+// Paused VMs are unpaused, suspended VMs are resumed when starting, or hard shut down when halting.
+func prepareVMApplianceVMsForPowerState(session *xenapi.Session, vmRefs []xenapi.VMRef, targetPowerState string) error {
+	for _, vmRef := range vmRefs {
+		powerState, err := xenapi.VM.GetPowerState(session, vmRef)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		switch powerState {
+		case xenapi.VMPowerStatePaused:
+			err = xenapi.VM.Unpause(session, vmRef)
+		case xenapi.VMPowerStateSuspended:
+			if targetPowerState == vmAppliancePowerStateRunning {
+				err = xenapi.VM.Resume(session, vmRef, false, false)
+			} else {
+				err = xenapi.VM.HardShutdown(session, vmRef)
+			}
+		case xenapi.VMPowerStateRunning, xenapi.VMPowerStateHalted, xenapi.VMPowerStateUnrecognized:
+		}
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+	return nil
+}
+
+func vmApplianceResourceModelUpdate(ctx context.Context, session *xenapi.Session, ref xenapi.VMApplianceRef, data vmApplianceResourceModel) error {
+	err := xenapi.VMAppliance.SetNameLabel(session, ref, data.NameLabel.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = xenapi.VMAppliance.SetNameDescription(session, ref, data.NameDescription.ValueString())
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	err = updateVMApplianceVMs(ctx, session, ref, data)
+	if err != nil {
+		return err
+	}
+	return updateVMAppliancePowerState(session, ref, data)
+}
+
+func cleanupVMApplianceResource(session *xenapi.Session, ref xenapi.VMApplianceRef) error {
+	vmRefs, err := xenapi.VMAppliance.GetVMs(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	for _, vmRef := range vmRefs {
+		err = removeVMApplianceVM(session, vmRef)
+		if err != nil {
+			return err
+		}
+	}
+	err = xenapi.VMAppliance.Destroy(session, ref)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
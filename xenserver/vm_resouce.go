@@ -16,6 +16,7 @@ var (
 	_ resource.Resource                = &vmResource{}
 	_ resource.ResourceWithConfigure   = &vmResource{}
 	_ resource.ResourceWithImportState = &vmResource{}
+	_ resource.ResourceWithModifyPlan  = &vmResource{}
 )
 
 func NewVMResource() resource.Resource {
@@ -260,6 +261,59 @@ func (r *vmResource) Update(ctx context.Context, req resource.UpdateRequest, res
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan plans vcpus_max when it's not set and reports the changes which require a reboot of the running VM at plan time
+func (r *vmResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// skip when creating or destroying the resource
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, state vmResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// vcpus_max is planned to be same with vcpus when it's not set
+	if plan.VCPUsMax.IsUnknown() && !plan.VCPUs.IsUnknown() {
+		plan.VCPUsMax = plan.VCPUs
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vcpus_max"), plan.VCPUsMax)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if r.session == nil {
+		return
+	}
+
+	// invalid vcpus settings are reported in the update stage
+	rebootRequired, err := isRebootRequired(plan, state)
+	if err != nil || !rebootRequired {
+		return
+	}
+	vmRef, err := xenapi.VM.GetByUUID(r.session, state.UUID.ValueString())
+	if err != nil {
+		return
+	}
+	vmPowerState, err := xenapi.VM.GetPowerState(r.session, vmRef)
+	if err != nil || vmPowerState != xenapi.VMPowerStateRunning {
+		return
+	}
+
+	if !plan.AllowReboot.IsUnknown() && plan.AllowReboot.ValueBool() {
+		resp.Diagnostics.AddWarning(
+			"VM will be rebooted",
+			"The change of static memory, vcpus_max or has_vendor_device requires a reboot, the running VM "+state.NameLabel.ValueString()+" will be cleanly shut down and started again to apply the change.",
+		)
+		return
+	}
+	resp.Diagnostics.AddError(
+		"VM reboot required",
+		"The change of static memory, vcpus_max or has_vendor_device can't be applied to the running VM "+state.NameLabel.ValueString()+", set allow_reboot_on_update to true to reboot the VM and apply the change.",
+	)
+}
+
 func (r *vmResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	tflog.Debug(ctx, "---> Delete VM resource")
 	var state vmResourceModel
@@ -2,6 +2,7 @@ package xenserver
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"testing"
 
@@ -86,8 +87,10 @@ func TestAccVMResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "4294967296"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_max", "4294967296"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "4"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "4"),
 					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "cores_per_socket"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "check_ip_timeout", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "allow_reboot_on_update", "false"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "default_ip", ""),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "boot_mode", "uefi"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "boot_order", "ncd"),
@@ -96,10 +99,14 @@ func TestAccVMResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hard_drive.0.mode", "RW"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hard_drive.0.bootable", "true"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.#", "1"),
-					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.%", "5"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.%", "11"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.device", "0"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.mac", "11:22:33:44:55:66"),
 					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "network_interface.0.vif_ref"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.locking_mode", "network_default"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.ipv4_allowed.#", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.qos_algorithm_type", ""),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "network_interface.0.mtu"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "other_config.%", "1"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "other_config.flag", "1"),
 					// Verify dynamic values have any value set in the state.
@@ -135,6 +142,7 @@ func TestAccVMResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "3221225472"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_max", "3221225472"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "2"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "cores_per_socket", "2"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hard_drive.#", "1"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hard_drive.0.mode", "RO"),
@@ -184,7 +192,7 @@ func TestAccLinuxVMResource(t *testing.T) {
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hard_drive.0.mode", "RW"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hard_drive.0.bootable", "true"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.#", "1"),
-					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.%", "5"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.%", "11"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.device", "0"),
 					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.mac", "11:22:33:44:55:66"),
 					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "network_interface.0.vif_ref"),
@@ -196,3 +204,461 @@ func TestAccLinuxVMResource(t *testing.T) {
 		},
 	})
 }
+
+func testAccVMCPUResourceConfig(template string, vcpus int, vcpus_max int, extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_network" "network" {}
+
+resource "xenserver_vm" "test_vm" {
+  name_label       = "Test VM CPU"
+  template_name    = "%s"
+  static_mem_max   = 4 * 1024 * 1024 * 1024
+  vcpus            = %d
+  vcpus_max        = %d
+  cores_per_socket = 2
+  network_interface = [
+    {
+      device       = "0"
+      network_uuid = data.xenserver_network.network.data_items[0].uuid,
+    },
+  ]
+  %s
+}
+`, template, vcpus, vcpus_max, extra_config)
+}
+
+func TestAccVMResourceCPU(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Testing with expected failure
+			{
+				Config:      providerConfig + testAccVMCPUResourceConfig("Windows 11", 6, 4, ""),
+				ExpectError: regexp.MustCompile(`vcpus 6 should not be greater than vcpus_max 4`),
+			},
+			{
+				Config:      providerConfig + testAccVMCPUResourceConfig("Windows 11", 2, 4, "vcpu_weight = 0"),
+				ExpectError: regexp.MustCompile(`vcpu_weight value must be between 1 and 65535`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMCPUResourceConfig("Windows 11", 2, 4, `
+  vcpu_weight = 512
+  vcpu_cap    = 100
+  vcpu_mask   = [0, 1]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "4"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "cores_per_socket", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_weight", "512"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_cap", "100"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_mask.#", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_mask.0", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_mask.1", "1"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccVMCPUResourceConfig("Windows 11", 4, 4, `
+  vcpu_weight = 256
+  vcpu_cap    = 0
+  vcpu_mask   = [0, 1, 2, 3]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "4"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "4"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_weight", "256"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_cap", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpu_mask.#", "4"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_vm.test_vm",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccVMMemoryResourceConfig(template string, static_mem_max int, dynamic_mem_min int, allow_reboot_on_update bool, extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_network" "network" {}
+
+resource "xenserver_vm" "test_vm" {
+  name_label             = "Test VM memory"
+  template_name          = "%s"
+  static_mem_max         = %d * 1024 * 1024 * 1024
+  dynamic_mem_min        = %d * 1024 * 1024 * 1024
+  vcpus                  = 2
+  allow_reboot_on_update = %t
+  network_interface = [
+    {
+      device       = "0"
+      network_uuid = data.xenserver_network.network.data_items[0].uuid,
+    },
+  ]
+  %s
+}
+`, template, static_mem_max, dynamic_mem_min, allow_reboot_on_update, extra_config)
+}
+
+func TestAccVMResourceMemory(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMMemoryResourceConfig("Windows 11", 4, 2, false, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "static_mem_max", "4294967296"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "2147483648"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_max", "4294967296"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "allow_reboot_on_update", "false"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccVMMemoryResourceConfig("Windows 11", 4, 3, true, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "static_mem_max", "4294967296"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "3221225472"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "allow_reboot_on_update", "true"),
+				),
+			},
+			{
+				Config: providerConfig + testAccVMMemoryResourceConfig("Windows 11", 6, 3, true, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "static_mem_max", "6442450944"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "3221225472"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_vm.test_vm",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// TestAccVMResourceCPURunning requires a template with the guest OS and guest agent installed, set by TEST_VM_TEMPLATE
+func TestAccVMResourceCPURunning(t *testing.T) {
+	template := os.Getenv("TEST_VM_TEMPLATE")
+	if template == "" {
+		t.Skip("Skipping TestAccVMResourceCPURunning test due to TEST_VM_TEMPLATE not set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMCPUResourceConfig(template, 2, 4, "check_ip_timeout = 600"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "4"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "default_ip"),
+				),
+			},
+			// Hot add VCPUs to the running VM
+			{
+				Config: providerConfig + testAccVMCPUResourceConfig(template, 4, 4, "check_ip_timeout = 600"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "4"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "4"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "default_ip"),
+				),
+			},
+			{
+				Config:      providerConfig + testAccVMCPUResourceConfig(template, 4, 6, "check_ip_timeout = 600"),
+				ExpectError: regexp.MustCompile(`VM reboot required`),
+			},
+			// Reboot the running VM to change vcpus_max
+			{
+				Config: providerConfig + testAccVMCPUResourceConfig(template, 4, 6, `
+  check_ip_timeout       = 600
+  allow_reboot_on_update = true`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus", "4"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "vcpus_max", "6"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "default_ip"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// TestAccVMResourceMemoryRunning requires a template with the guest OS and guest agent installed, set by TEST_VM_TEMPLATE
+func TestAccVMResourceMemoryRunning(t *testing.T) {
+	template := os.Getenv("TEST_VM_TEMPLATE")
+	if template == "" {
+		t.Skip("Skipping TestAccVMResourceMemoryRunning test due to TEST_VM_TEMPLATE not set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMMemoryResourceConfig(template, 4, 2, false, "check_ip_timeout = 600"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "static_mem_max", "4294967296"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "2147483648"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "default_ip"),
+				),
+			},
+			// Change the dynamic memory range of the running VM
+			{
+				Config: providerConfig + testAccVMMemoryResourceConfig(template, 4, 3, false, "check_ip_timeout = 600"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "static_mem_max", "4294967296"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "3221225472"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "default_ip"),
+				),
+			},
+			{
+				Config:      providerConfig + testAccVMMemoryResourceConfig(template, 6, 3, false, "check_ip_timeout = 600"),
+				ExpectError: regexp.MustCompile(`VM reboot required`),
+			},
+			// Reboot the running VM to change the static memory
+			{
+				Config: providerConfig + testAccVMMemoryResourceConfig(template, 6, 3, true, "check_ip_timeout = 600"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "static_mem_max", "6442450944"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "dynamic_mem_min", "3221225472"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "default_ip"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccVMAdvancedResourceConfig(extra_config string) string {
+	return fmt.Sprintf(`
+data "xenserver_network" "network" {}
+
+resource "xenserver_vm" "test_vm" {
+  name_label     = "Test VM advanced settings"
+  template_name  = "Windows 11"
+  static_mem_max = 4 * 1024 * 1024 * 1024
+  vcpus          = 2
+  network_interface = [
+    {
+      device       = "0"
+      network_uuid = data.xenserver_network.network.data_items[0].uuid,
+    },
+  ]
+  bios_strings = {
+    "system-manufacturer" = "Test Manufacturer"
+  }
+  %s
+}
+`, extra_config)
+}
+
+func TestAccVMResourceAdvanced(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Testing with expected failure
+			{
+				Config: providerConfig + testAccVMAdvancedResourceConfig(`
+  platform = {
+    "cores-per-socket" = "2"
+  }`),
+				ExpectError: regexp.MustCompile(`value must be none of`),
+			},
+			{
+				Config: providerConfig + testAccVMAdvancedResourceConfig(`
+  xenstore_data = {
+    "hostname" = "test"
+  }`),
+				ExpectError: regexp.MustCompile(`the key must start with 'vm-data'`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMAdvancedResourceConfig(`
+  platform = {
+    "viridian" = "true"
+  }
+  xenstore_data = {
+    "vm-data/hostname" = "test-vm"
+  }
+  hvm_shadow_multiplier = 2
+  actions_after_crash   = "destroy"
+  tags                  = ["test", "terraform"]
+  blocked_operations = {
+    "suspend" = "not supported by the guest"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "platform.%", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "platform.viridian", "true"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "xenstore_data.%", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "xenstore_data.vm-data/hostname", "test-vm"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hvm_shadow_multiplier", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "actions_after_crash", "destroy"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "bios_strings.%", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "bios_strings.system-manufacturer", "Test Manufacturer"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr("xenserver_vm.test_vm", "tags.*", "terraform"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "blocked_operations.%", "1"),
+					resource.TestCheckResourceAttrSet("xenserver_vm.test_vm", "has_vendor_device"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_vm.test_vm",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccVMAdvancedResourceConfig(`
+  platform = {
+    "viridian"                = "false"
+    "viridian_time_ref_count" = "true"
+  }
+  xenstore_data         = {}
+  hvm_shadow_multiplier = 1
+  actions_after_crash   = "restart"
+  has_vendor_device     = true
+  tags                  = ["test"]
+  blocked_operations    = {}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "platform.%", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "platform.viridian", "false"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "xenstore_data.%", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "hvm_shadow_multiplier", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "actions_after_crash", "restart"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "has_vendor_device", "true"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "tags.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "blocked_operations.%", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccVMNetworkInterfaceResourceConfig(network_interface string) string {
+	return fmt.Sprintf(`
+data "xenserver_network" "network" {}
+
+resource "xenserver_vm" "test_vm" {
+  name_label     = "Test VM network interface"
+  template_name  = "Windows 11"
+  static_mem_max = 4 * 1024 * 1024 * 1024
+  vcpus          = 2
+  network_interface = [
+    {
+      device       = "0"
+      network_uuid = data.xenserver_network.network.data_items[0].uuid,
+      %s
+    },
+  ]
+}
+`, network_interface)
+}
+
+func TestAccVMResourceNetworkInterface(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Testing with expected failure
+			{
+				Config:      providerConfig + testAccVMNetworkInterfaceResourceConfig(`locking_mode = "invalid"`),
+				ExpectError: regexp.MustCompile(`locking_mode value must be one of`),
+			},
+			{
+				Config:      providerConfig + testAccVMNetworkInterfaceResourceConfig(`ipv4_allowed = ["invalid"]`),
+				ExpectError: regexp.MustCompile(`Input is not a valid IPv4 address`),
+			},
+			{
+				Config:      providerConfig + testAccVMNetworkInterfaceResourceConfig(`ipv4_allowed = ["999.999.999.999"]`),
+				ExpectError: regexp.MustCompile(`Input is not a valid IPv4 address`),
+			},
+			{
+				Config:      providerConfig + testAccVMNetworkInterfaceResourceConfig(`ipv6_allowed = ["fd00::1::10"]`),
+				ExpectError: regexp.MustCompile(`Input is not a valid IPv6 address`),
+			},
+			{
+				Config: providerConfig + testAccVMNetworkInterfaceResourceConfig(`other_config = {
+        mtu = "invalid"
+      }`),
+				ExpectError: regexp.MustCompile(`should be an integer between 68 and 65535`),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccVMNetworkInterfaceResourceConfig(`
+      locking_mode       = "locked"
+      ipv4_allowed       = ["10.0.0.10"]
+      ipv6_allowed       = ["fd00::10"]
+      qos_algorithm_type = "ratelimit"
+      qos_kbps           = 10240
+      mtu                = 1400`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.locking_mode", "locked"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.ipv4_allowed.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.ipv4_allowed.0", "10.0.0.10"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.ipv6_allowed.#", "1"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.qos_algorithm_type", "ratelimit"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.qos_kbps", "10240"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.mtu", "1400"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.other_config.%", "0"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xenserver_vm.test_vm",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccVMNetworkInterfaceResourceConfig(`
+      locking_mode       = "unlocked"
+      ipv4_allowed       = ["10.0.0.11", "10.0.0.12"]
+      qos_algorithm_type = ""
+      mtu                = 1500`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.locking_mode", "unlocked"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.ipv4_allowed.#", "2"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.ipv6_allowed.#", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.qos_algorithm_type", ""),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.qos_kbps", "0"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.mtu", "1500"),
+				),
+			},
+			// Deprecated "mtu" key in other_config
+			{
+				Config: providerConfig + testAccVMNetworkInterfaceResourceConfig(`other_config = {
+        mtu = "9000"
+      }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.mtu", "9000"),
+					resource.TestCheckResourceAttr("xenserver_vm.test_vm", "network_interface.0.other_config.mtu", "9000"),
+				),
+			},
+			{
+				Config: providerConfig + testAccVMNetworkInterfaceResourceConfig(`
+      mtu = 1500
+      other_config = {
+        mtu = "9000"
+      }`),
+				ExpectError: regexp.MustCompile(`should not be set to different values`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -12,14 +12,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -133,26 +139,39 @@ type vmRecordData struct {
 
 // vmResourceModel describes the resource data model.
 type vmResourceModel struct {
-	NameLabel         types.String `tfsdk:"name_label"`
-	NameDescription   types.String `tfsdk:"name_description"`
-	TemplateName      types.String `tfsdk:"template_name"`
-	StaticMemMin      types.Int64  `tfsdk:"static_mem_min"`
-	StaticMemMax      types.Int64  `tfsdk:"static_mem_max"`
-	DynamicMemMin     types.Int64  `tfsdk:"dynamic_mem_min"`
-	DynamicMemMax     types.Int64  `tfsdk:"dynamic_mem_max"`
-	VCPUs             types.Int32  `tfsdk:"vcpus"`
-	BootMode          types.String `tfsdk:"boot_mode"`
-	BootOrder         types.String `tfsdk:"boot_order"`
-	CorePerSocket     types.Int32  `tfsdk:"cores_per_socket"`
-	OtherConfig       types.Map    `tfsdk:"other_config"`
-	HardDrive         types.Set    `tfsdk:"hard_drive"`
-	SRForFullDiskCopy types.String `tfsdk:"sr_for_full_disk_copy"`
-	NetworkInterface  types.Set    `tfsdk:"network_interface"`
-	CDROM             types.String `tfsdk:"cdrom"`
-	UUID              types.String `tfsdk:"uuid"`
-	ID                types.String `tfsdk:"id"`
-	DefaultIP         types.String `tfsdk:"default_ip"`
-	CheckIPTimeout    types.Int64  `tfsdk:"check_ip_timeout"`
+	NameLabel           types.String  `tfsdk:"name_label"`
+	NameDescription     types.String  `tfsdk:"name_description"`
+	TemplateName        types.String  `tfsdk:"template_name"`
+	StaticMemMin        types.Int64   `tfsdk:"static_mem_min"`
+	StaticMemMax        types.Int64   `tfsdk:"static_mem_max"`
+	DynamicMemMin       types.Int64   `tfsdk:"dynamic_mem_min"`
+	DynamicMemMax       types.Int64   `tfsdk:"dynamic_mem_max"`
+	VCPUs               types.Int32   `tfsdk:"vcpus"`
+	VCPUsMax            types.Int32   `tfsdk:"vcpus_max"`
+	VCPUWeight          types.Int32   `tfsdk:"vcpu_weight"`
+	VCPUCap             types.Int32   `tfsdk:"vcpu_cap"`
+	VCPUMask            types.List    `tfsdk:"vcpu_mask"`
+	BootMode            types.String  `tfsdk:"boot_mode"`
+	BootOrder           types.String  `tfsdk:"boot_order"`
+	CorePerSocket       types.Int32   `tfsdk:"cores_per_socket"`
+	OtherConfig         types.Map     `tfsdk:"other_config"`
+	Platform            types.Map     `tfsdk:"platform"`
+	XenstoreData        types.Map     `tfsdk:"xenstore_data"`
+	HVMShadowMultiplier types.Float64 `tfsdk:"hvm_shadow_multiplier"`
+	ActionsAfterCrash   types.String  `tfsdk:"actions_after_crash"`
+	HasVendorDevice     types.Bool    `tfsdk:"has_vendor_device"`
+	BiosStrings         types.Map     `tfsdk:"bios_strings"`
+	Tags                types.Set     `tfsdk:"tags"`
+	BlockedOperations   types.Map     `tfsdk:"blocked_operations"`
+	HardDrive           types.Set     `tfsdk:"hard_drive"`
+	SRForFullDiskCopy   types.String  `tfsdk:"sr_for_full_disk_copy"`
+	NetworkInterface    types.Set     `tfsdk:"network_interface"`
+	CDROM               types.String  `tfsdk:"cdrom"`
+	UUID                types.String  `tfsdk:"uuid"`
+	ID                  types.String  `tfsdk:"id"`
+	DefaultIP           types.String  `tfsdk:"default_ip"`
+	CheckIPTimeout      types.Int64   `tfsdk:"check_ip_timeout"`
+	AllowReboot         types.Bool    `tfsdk:"allow_reboot_on_update"`
 }
 
 func vmSchema() map[string]schema.Attribute {
@@ -182,18 +201,58 @@ func vmSchema() map[string]schema.Attribute {
 			Required:            true,
 		},
 		"dynamic_mem_min": schema.Int64Attribute{
-			MarkdownDescription: "Dynamic minimum memory (bytes), default same with `static_mem_max`.",
+			MarkdownDescription: "Dynamic minimum memory (bytes), default same with `static_mem_max`. It can be changed without reboot when the VM is running.",
 			Optional:            true,
 			Computed:            true,
 		},
 		"dynamic_mem_max": schema.Int64Attribute{
-			MarkdownDescription: "Dynamic maximum memory (bytes), default same with `static_mem_max`.",
+			MarkdownDescription: "Dynamic maximum memory (bytes), default same with `static_mem_max`. It can be changed without reboot when the VM is running.",
 			Optional:            true,
 			Computed:            true,
 		},
 		"vcpus": schema.Int32Attribute{
-			MarkdownDescription: "The number of VCPUs for the virtual machine.",
-			Required:            true,
+			MarkdownDescription: "The number of VCPUs for the virtual machine." + "<br />" +
+				"When the virtual machine is running, VCPUs can be hot added up to `vcpus_max`, but not removed.",
+			Required: true,
+		},
+		"vcpus_max": schema.Int32Attribute{
+			MarkdownDescription: "The maximum number of VCPUs for the virtual machine, default same with `vcpus`." + "<br />" +
+				"Set it greater than `vcpus` to allow hot adding VCPUs to the running virtual machine." +
+				"\n\n-> **Note:** `vcpus_max` is not allowed to be updated when the virtual machine is running.",
+			Optional: true,
+			Computed: true,
+			Validators: []validator.Int32{
+				int32validator.AtLeast(1),
+			},
+		},
+		"vcpu_weight": schema.Int32Attribute{
+			MarkdownDescription: "The scheduling weight of the VCPUs, default inherited from the template." + "<br />" +
+				"A VM with a weight of 512 gets twice as much CPU as a VM with a weight of 256 on a contended host. The value is between `1` and `65535`.",
+			Optional: true,
+			Computed: true,
+			Validators: []validator.Int32{
+				int32validator.Between(1, 65535),
+			},
+		},
+		"vcpu_cap": schema.Int32Attribute{
+			MarkdownDescription: "The maximum amount of CPU the VM can consume, expressed in percentage of one physical CPU, default inherited from the template." + "<br />" +
+				"For example, `100` is one physical CPU, `50` is half a CPU. `0` means no upper cap.",
+			Optional: true,
+			Computed: true,
+			Validators: []validator.Int32{
+				int32validator.AtLeast(0),
+			},
+		},
+		"vcpu_mask": schema.ListAttribute{
+			MarkdownDescription: "The list of physical CPUs the VCPUs are pinned to, default inherited from the template." + "<br />" +
+				"For example, `[0, 1]` pins the VCPUs to the physical CPU 0 and 1." +
+				"\n\n-> **Note:** The change of `vcpu_mask` takes effect on the next boot of the virtual machine.",
+			ElementType: types.Int32Type,
+			Optional:    true,
+			Computed:    true,
+			Validators: []validator.List{
+				listvalidator.ValueInt32sAre(int32validator.AtLeast(0)),
+			},
 		},
 		"cores_per_socket": schema.Int32Attribute{
 			MarkdownDescription: "The number of core pre socket for the virtual machine, default inherited from the template.",
@@ -257,6 +316,77 @@ func vmSchema() map[string]schema.Attribute {
 			ElementType:         types.StringType,
 			Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
 		},
+		"platform": schema.MapAttribute{
+			MarkdownDescription: "The platform flags of the virtual machine, default to be `{}`." + "<br />" +
+				"Only the keys set in this attribute are managed, the platform flags inherited from the template are kept. Use `cores_per_socket` and `boot_mode` instead of the `\"cores-per-socket\"` and `\"secureboot\"` keys." +
+				"\n\n-> **Note:** The change of `platform` takes effect on the next boot of the virtual machine.",
+			Optional:    true,
+			Computed:    true,
+			ElementType: types.StringType,
+			Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+			Validators: []validator.Map{
+				mapvalidator.KeysAre(stringvalidator.NoneOf("cores-per-socket", "secureboot")),
+			},
+		},
+		"xenstore_data": schema.MapAttribute{
+			MarkdownDescription: "The data to be inserted into the xenstore tree of the virtual machine, default to be `{}`." + "<br />" +
+				"The keys must start with `\"vm-data\"`, for example, `\"vm-data/hostname\"`. Only the keys set in this attribute are managed.",
+			Optional:    true,
+			Computed:    true,
+			ElementType: types.StringType,
+			Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+			Validators: []validator.Map{
+				mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^vm-data(/.*)?$`), "the key must start with 'vm-data'")),
+			},
+		},
+		"hvm_shadow_multiplier": schema.Float64Attribute{
+			MarkdownDescription: "The multiplier applied to the amount of shadow memory that will be made available to the guest, default inherited from the template.",
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.Float64{
+				float64validator.AtLeast(1),
+			},
+		},
+		"actions_after_crash": schema.StringAttribute{
+			MarkdownDescription: "The action to take after the guest has crashed, default inherited from the template." + "<br />" +
+				"This value can be one of [`\"destroy\", \"coredump_and_destroy\", \"restart\", \"coredump_and_restart\", \"preserve\", \"rename_restart\"`].",
+			Optional: true,
+			Computed: true,
+			Validators: []validator.String{
+				stringvalidator.OneOf("destroy", "coredump_and_destroy", "restart", "coredump_and_restart", "preserve", "rename_restart"),
+			},
+		},
+		"has_vendor_device": schema.BoolAttribute{
+			MarkdownDescription: "Whether to expose the emulated PCI device used by Windows Update to deliver the PV drivers, default inherited from the template." +
+				"\n\n-> **Note:** `has_vendor_device` is not allowed to be updated when the virtual machine is running, unless `allow_reboot_on_update` is `true`.",
+			Optional: true,
+			Computed: true,
+		},
+		"bios_strings": schema.MapAttribute{
+			MarkdownDescription: "The custom BIOS strings of the virtual machine, default to be `{}`." + "<br />" +
+				"Only some of the keys can be set, for example, `\"bios-vendor\"`, `\"system-manufacturer\"`, `\"system-product-name\"` and `\"system-serial-number\"`." +
+				"\n\n-> **Note:** The BIOS strings can only be set once, the change of `bios_strings` will recreate the virtual machine.",
+			Optional:    true,
+			Computed:    true,
+			ElementType: types.StringType,
+			Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+			PlanModifiers: []planmodifier.Map{
+				mapplanmodifier.RequiresReplace(),
+			},
+		},
+		"tags": schema.SetAttribute{
+			MarkdownDescription: "The set of tags of the virtual machine, default inherited from the template.",
+			Optional:            true,
+			Computed:            true,
+			ElementType:         types.StringType,
+		},
+		"blocked_operations": schema.MapAttribute{
+			MarkdownDescription: "The operations which are blocked on the virtual machine, default inherited from the template." + "<br />" +
+				"The key is the operation name, for example, `\"destroy\"` or `\"clean_shutdown\"`, and the value is the reason why it is blocked.",
+			Optional:    true,
+			Computed:    true,
+			ElementType: types.StringType,
+		},
 		"check_ip_timeout": schema.Int64Attribute{
 			MarkdownDescription: "The duration for checking the IP address of the virtual machine. default is 0 seconds, once the value greater than 0, the provider will check the IP address of the virtual machine in the specified duration.",
 			Optional:            true,
@@ -266,6 +396,14 @@ func vmSchema() map[string]schema.Attribute {
 				int64validator.AtLeast(0),
 			},
 		},
+		"allow_reboot_on_update": schema.BoolAttribute{
+			MarkdownDescription: "Set to `true` to allow the provider to reboot the running virtual machine when the update can't be applied live, default to be `false`." + "<br />" +
+				"The change of `static_mem_min`, `static_mem_max`, `vcpus_max` or `has_vendor_device` requires a reboot, the virtual machine will be cleanly shut down, updated and started again." + "<br />" +
+				"The change of `dynamic_mem_min` and `dynamic_mem_max` is applied to the running virtual machine without reboot.",
+			Optional: true,
+			Computed: true,
+			Default:  booldefault.StaticBool(false),
+		},
 		"default_ip": schema.StringAttribute{
 			MarkdownDescription: "The default IP address of the virtual machine.",
 			Computed:            true,
@@ -712,6 +850,7 @@ func updateOtherConfigFromPlan(ctx context.Context, session *xenapi.Session, vmR
 
 	vmOtherConfig["tf_other_config_keys"] = strings.Join(tfOtherConfigKeys, ",")
 	vmOtherConfig["tf_check_ip_timeout"] = plan.CheckIPTimeout.String()
+	vmOtherConfig["tf_allow_reboot_on_update"] = plan.AllowReboot.String()
 	vmOtherConfig["tf_template_name"] = plan.TemplateName.ValueString()
 	vmOtherConfig["tf_sr_for_full_disk_copy"] = plan.SRForFullDiskCopy.ValueString()
 
@@ -766,13 +905,24 @@ func updateVMResourceModelComputed(ctx context.Context, session *xenapi.Session,
 	data.DynamicMemMin = types.Int64Value(int64(vmRecord.MemoryDynamicMin))
 	data.DynamicMemMax = types.Int64Value(int64(vmRecord.MemoryDynamicMax))
 
+	vcpusMax, err := ToInt32(vmRecord.VCPUsMax)
+	if err != nil {
+		return err
+	}
+	data.VCPUsMax = types.Int32Value(vcpusMax)
+
+	err = getVCPUsParamsFromVMRecord(ctx, vmRecord, data)
+	if err != nil {
+		return err
+	}
+
 	socketInt, err := getCorePerSocket(vmRecord)
 	if err != nil {
 		return err
 	}
 	data.CorePerSocket = types.Int32Value(socketInt)
 
-	data.NetworkInterface, err = getVIFsFromVMRecord(ctx, session, vmRecord)
+	data.NetworkInterface, err = getVIFsFromVMRecord(ctx, session, vmRecord, data.NetworkInterface)
 	if err != nil {
 		return err
 	}
@@ -820,10 +970,59 @@ func updateVMResourceModelComputed(ctx context.Context, session *xenapi.Session,
 		data.DefaultIP = types.StringValue(ip)
 	}
 
+	if _, ok := vmRecord.OtherConfig["tf_allow_reboot_on_update"]; ok {
+		allowReboot, err := strconv.ParseBool(vmRecord.OtherConfig["tf_allow_reboot_on_update"])
+		if err != nil {
+			return errors.New("unable to convert allow_reboot_on_update to a bool value")
+		}
+		data.AllowReboot = types.BoolValue(allowReboot)
+	}
+
 	if _, ok := vmRecord.OtherConfig["tf_sr_for_full_disk_copy"]; ok {
 		data.SRForFullDiskCopy = types.StringValue(vmRecord.OtherConfig["tf_sr_for_full_disk_copy"])
 	}
 
+	return getAdvancedSettingsFromVMRecord(ctx, vmRecord, data)
+}
+
+func getAdvancedSettingsFromVMRecord(ctx context.Context, vmRecord xenapi.VMRecord, data *vmResourceModel) error {
+	var err error
+	var diags diag.Diagnostics
+	// only keep the keys which configured by user
+	data.Platform, err = getTFManagedMap(ctx, vmRecord.Platform, vmRecord.OtherConfig["tf_platform_keys"])
+	if err != nil {
+		return err
+	}
+	data.XenstoreData, err = getTFManagedMap(ctx, vmRecord.XenstoreData, vmRecord.OtherConfig["tf_xenstore_data_keys"])
+	if err != nil {
+		return err
+	}
+	data.BiosStrings, err = getTFManagedMap(ctx, vmRecord.BiosStrings, vmRecord.OtherConfig["tf_bios_strings_keys"])
+	if err != nil {
+		return err
+	}
+
+	data.HVMShadowMultiplier = types.Float64Value(vmRecord.HVMShadowMultiplier)
+	data.ActionsAfterCrash = types.StringValue(string(vmRecord.ActionsAfterCrash))
+	data.HasVendorDevice = types.BoolValue(vmRecord.HasVendorDevice)
+
+	tags := vmRecord.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	data.Tags, diags = types.SetValueFrom(ctx, types.StringType, tags)
+	if diags.HasError() {
+		return errors.New("unable to read VM tags")
+	}
+	blockedOperations := make(map[string]string)
+	for operation, reason := range vmRecord.BlockedOperations {
+		blockedOperations[string(operation)] = reason
+	}
+	data.BlockedOperations, diags = types.MapValueFrom(ctx, types.StringType, blockedOperations)
+	if diags.HasError() {
+		return errors.New("unable to read VM blocked operations")
+	}
+
 	return nil
 }
 
@@ -832,11 +1031,11 @@ func updateVMResourceModel(ctx context.Context, session *xenapi.Session, vmRecor
 	data.NameLabel = types.StringValue(vmRecord.NameLabel)
 	data.TemplateName = types.StringValue(vmRecord.OtherConfig["tf_template_name"])
 	data.StaticMemMax = types.Int64Value(int64(vmRecord.MemoryStaticMax))
-	vcpusMax, err := ToInt32(vmRecord.VCPUsMax)
+	vcpus, err := ToInt32(vmRecord.VCPUsAtStartup)
 	if err != nil {
 		return err
 	}
-	data.VCPUs = types.Int32Value(vcpusMax)
+	data.VCPUs = types.Int32Value(vcpus)
 	return updateVMResourceModelComputed(ctx, session, vmRecord, data)
 }
 
@@ -882,25 +1081,77 @@ func getVBDsFromVMRecord(ctx context.Context, session *xenapi.Session, vmRecord
 }
 
 func getOtherConfigFromVMRecord(ctx context.Context, vmRecord xenapi.VMRecord) (basetypes.MapValue, error) {
-	otherConfig := make(map[string]string)
-	for key := range vmRecord.OtherConfig {
-		if slices.Contains(strings.Split(vmRecord.OtherConfig["tf_other_config_keys"], ","), key) {
-			otherConfig[key] = vmRecord.OtherConfig[key]
+	return getTFManagedMap(ctx, vmRecord.OtherConfig, vmRecord.OtherConfig["tf_other_config_keys"])
+}
+
+// getTFManagedMap returns the entries of the keys which are managed by terraform, the managed
+// keys are recorded as a comma separated string in the VM other config.
+func getTFManagedMap(ctx context.Context, values map[string]string, managedKeys string) (basetypes.MapValue, error) {
+	managedValues := make(map[string]string)
+	keys := strings.Split(managedKeys, ",")
+	for key, value := range values {
+		if slices.Contains(keys, key) {
+			managedValues[key] = value
 		}
 	}
 
-	otherConfigMap, diags := types.MapValueFrom(ctx, types.StringType, otherConfig)
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, managedValues)
 	if diags.HasError() {
-		return otherConfigMap, errors.New("unable to get other config map value")
+		return mapValue, errors.New("unable to get map value")
 	}
 
-	return otherConfigMap, nil
+	return mapValue, nil
 }
 
-func getVIFsFromVMRecord(ctx context.Context, session *xenapi.Session, vmRecord xenapi.VMRecord) (basetypes.SetValue, error) {
+// mergeTFManagedMap removes the keys managed by terraform from the original values and adds the planned ones,
+// the other keys, such as the ones inherited from the template, are kept. It returns the merged values and
+// the new managed keys.
+func mergeTFManagedMap(ctx context.Context, values map[string]string, managedKeys string, plan types.Map) (map[string]string, string, error) {
+	planValues := make(map[string]string)
+	if !plan.IsUnknown() {
+		diags := plan.ElementsAs(ctx, &planValues, false)
+		if diags.HasError() {
+			return nil, "", errors.New("unable to read map elements")
+		}
+	}
+
+	merged := make(map[string]string)
+	for key, value := range values {
+		merged[key] = value
+	}
+	for _, key := range strings.Split(managedKeys, ",") {
+		delete(merged, key)
+	}
+
+	keys := []string{}
+	for key, value := range planValues {
+		merged[key] = value
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return merged, strings.Join(keys, ","), nil
+}
+
+// getVIFsFromVMRecord reads the VIFs from the VM record, priorVIFs are the VIFs in the plan or state
+// which tell the devices still using the deprecated "mtu" key in other config
+func getVIFsFromVMRecord(ctx context.Context, session *xenapi.Session, vmRecord xenapi.VMRecord, priorVIFs types.Set) (basetypes.SetValue, error) {
 	vifSet := []vifResourceModel{}
 	var setValue basetypes.SetValue
 	var diags diag.Diagnostics
+	mtuKeyDevices := make(map[string]bool)
+	if !priorVIFs.IsNull() && !priorVIFs.IsUnknown() {
+		vifs := []vifResourceModel{}
+		diags = priorVIFs.ElementsAs(ctx, &vifs, false)
+		if diags.HasError() {
+			return setValue, errors.New("unable to get VIFs in prior data")
+		}
+		for _, vif := range vifs {
+			if _, ok := vif.OtherConfig.Elements()["mtu"]; ok {
+				mtuKeyDevices[vif.Device.ValueString()] = true
+			}
+		}
+	}
 	for _, vifRef := range vmRecord.VIFs {
 		vifRecord, err := xenapi.VIF.GetRecord(session, vifRef)
 		if err != nil {
@@ -920,9 +1171,9 @@ func getVIFsFromVMRecord(ctx context.Context, session *xenapi.Session, vmRecord
 			Device:  types.StringValue(vifRecord.Device),
 		}
 
-		vif.OtherConfig, diags = types.MapValueFrom(ctx, types.StringType, vifRecord.OtherConfig)
-		if diags.HasError() {
-			return setValue, errors.New("unable to read VIF other config")
+		err = updateVIFModelFromRecord(ctx, vifRecord, &vif, mtuKeyDevices[vifRecord.Device])
+		if err != nil {
+			return setValue, err
 		}
 
 		vifSet = append(vifSet, vif)
@@ -972,6 +1223,12 @@ func setVMMemory(session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceMod
 	return nil
 }
 
+func isStaticMemoryChanged(plan vmResourceModel, state vmResourceModel) bool {
+	planMemorySetting := getVMMemory(plan)
+	stateMemorySetting := getVMMemory(state)
+	return planMemorySetting.staticMemMin != stateMemorySetting.staticMemMin || planMemorySetting.staticMemMax != stateMemorySetting.staticMemMax
+}
+
 func updateVMMemory(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel, state vmResourceModel) error {
 	planMemorySetting := getVMMemory(plan)
 	stateMemorySetting := getVMMemory(state)
@@ -984,7 +1241,16 @@ func updateVMMemory(ctx context.Context, session *xenapi.Session, vmRef xenapi.V
 		return errors.New(err.Error())
 	}
 	if vmState == xenapi.VMPowerStateRunning {
-		return errors.New("unable to change memory for a running VM")
+		if isStaticMemoryChanged(plan, state) {
+			return errors.New("unable to change static memory for a running VM, set allow_reboot_on_update to true to reboot the VM and apply the change")
+		}
+		// only the dynamic range is allowed to be changed for a running VM
+		tflog.Debug(ctx, "---> Change the dynamic memory range of the running VM. <---")
+		err = xenapi.VM.SetMemoryDynamicRange(session, vmRef, planMemorySetting.dynamicMemMin, planMemorySetting.dynamicMemMax)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+		return nil
 	}
 	err = xenapi.VM.SetMemoryLimits(session, vmRef, planMemorySetting.staticMemMin, planMemorySetting.staticMemMax, planMemorySetting.dynamicMemMin, planMemorySetting.dynamicMemMax)
 	if err != nil {
@@ -994,6 +1260,59 @@ func updateVMMemory(ctx context.Context, session *xenapi.Session, vmRef xenapi.V
 	return nil
 }
 
+// isRebootRequired returns true if the planned changes can't be applied to a running VM
+func isRebootRequired(plan vmResourceModel, state vmResourceModel) (bool, error) {
+	if isStaticMemoryChanged(plan, state) {
+		return true, nil
+	}
+	if !plan.HasVendorDevice.IsUnknown() && plan.HasVendorDevice != state.HasVendorDevice {
+		return true, nil
+	}
+	vcpusMax, err := getVCPUsMax(plan)
+	if err != nil {
+		return false, err
+	}
+	return vcpusMax != int(state.VCPUsMax.ValueInt32()), nil
+}
+
+// shutdownVMForUpdate cleanly shuts down the running VM if the planned changes require a reboot and
+// allow_reboot_on_update is set, it returns true if the VM has been shut down and needs to be started again.
+func shutdownVMForUpdate(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel, state vmResourceModel) (bool, error) {
+	rebootRequired, err := isRebootRequired(plan, state)
+	if err != nil {
+		return false, err
+	}
+	if !rebootRequired || !plan.AllowReboot.ValueBool() {
+		return false, nil
+	}
+	vmPowerState, err := xenapi.VM.GetPowerState(session, vmRef)
+	if err != nil {
+		return false, errors.New(err.Error())
+	}
+	if vmPowerState != xenapi.VMPowerStateRunning {
+		return false, nil
+	}
+	tflog.Debug(ctx, "---> Shut down the running VM to apply the changes. <---")
+	err = xenapi.VM.CleanShutdown(session, vmRef)
+	if err != nil {
+		return false, errors.New(err.Error())
+	}
+	return true, nil
+}
+
+// getVCPUsMax returns the planned vcpus_max, it's same with vcpus if not set
+func getVCPUsMax(plan vmResourceModel) (int, error) {
+	vcpus := int(plan.VCPUs.ValueInt32())
+	if plan.VCPUsMax.IsUnknown() || plan.VCPUsMax.IsNull() {
+		return vcpus, nil
+	}
+	vcpusMax := int(plan.VCPUsMax.ValueInt32())
+	if vcpus > vcpusMax {
+		return 0, fmt.Errorf("vcpus %d should not be greater than vcpus_max %d", vcpus, vcpusMax)
+	}
+	return vcpusMax, nil
+}
+
 func changeVCPUSettings(session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel) error {
 	vmPowerState, err := xenapi.VM.GetPowerState(session, vmRef)
 	if err != nil {
@@ -1004,24 +1323,28 @@ func changeVCPUSettings(session *xenapi.Session, vmRef xenapi.VMRef, plan vmReso
 	}
 
 	vcpus := int(plan.VCPUs.ValueInt32())
+	vcpusMax, err := getVCPUsMax(plan)
+	if err != nil {
+		return err
+	}
 	vcpusAtStartup, err := xenapi.VM.GetVCPUsAtStartup(session, vmRef)
 	if err != nil {
 		return errors.New(err.Error())
 	}
 	// VCPU values must satisfy: 0 < VCPUs_at_startup ≤ VCPUs_max
-	if vcpusAtStartup > vcpus {
-		// reducing VCPUs_at_startup: we need to change this value first, and then the VCPUs_max
+	if vcpusAtStartup > vcpusMax {
+		// reducing VCPUs_max below VCPUs_at_startup: we need to change VCPUs_at_startup first, and then the VCPUs_max
 		err := xenapi.VM.SetVCPUsAtStartup(session, vmRef, vcpus)
 		if err != nil {
 			return errors.New(err.Error())
 		}
-		err = xenapi.VM.SetVCPUsMax(session, vmRef, vcpus)
+		err = xenapi.VM.SetVCPUsMax(session, vmRef, vcpusMax)
 		if err != nil {
 			return errors.New(err.Error())
 		}
 	} else {
-		// increasing VCPUs_at_startup: we need to change the VCPUs_max first
-		err := xenapi.VM.SetVCPUsMax(session, vmRef, vcpus)
+		// otherwise change the VCPUs_max first
+		err := xenapi.VM.SetVCPUsMax(session, vmRef, vcpusMax)
 		if err != nil {
 			return errors.New(err.Error())
 		}
@@ -1034,23 +1357,162 @@ func changeVCPUSettings(session *xenapi.Session, vmRef xenapi.VMRef, plan vmReso
 	return nil
 }
 
+// hotAddVCPUs increases the VCPUs of a running VM up to VCPUs_max, the VCPUs can't be removed from a running VM
+func hotAddVCPUs(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel, state vmResourceModel) error {
+	vcpusMax, err := getVCPUsMax(plan)
+	if err != nil {
+		return err
+	}
+	if vcpusMax != int(state.VCPUsMax.ValueInt32()) {
+		return errors.New("unable to change vcpus_max for a running VM, set allow_reboot_on_update to true to reboot the VM and apply the change")
+	}
+	if plan.VCPUs.ValueInt32() < state.VCPUs.ValueInt32() {
+		return errors.New("unable to reduce vcpus for a running VM")
+	}
+	tflog.Debug(ctx, "---> Hot add VCPUs to the running VM. <---")
+	err = xenapi.VM.SetVCPUsNumberLive(session, vmRef, int(plan.VCPUs.ValueInt32()))
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	return nil
+}
+
 func updateVMCPUs(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel, state vmResourceModel) error {
-	if plan.VCPUs == state.VCPUs {
+	vcpusMax, err := getVCPUsMax(plan)
+	if err != nil {
+		return err
+	}
+	if plan.VCPUs == state.VCPUs && vcpusMax == int(state.VCPUsMax.ValueInt32()) {
 		tflog.Debug(ctx, "---> No vcpus change, skip update VM CPUs. <---")
 		return nil
 	}
+	vmPowerState, err := xenapi.VM.GetPowerState(session, vmRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if vmPowerState == xenapi.VMPowerStateRunning {
+		return hotAddVCPUs(ctx, session, vmRef, plan, state)
+	}
 	return changeVCPUSettings(session, vmRef, plan)
 }
 
+// updateVCPUsParams sets the CPU scheduling parameters of the VM, the weight and cap are applied to the running VM directly,
+// the mask takes effect on the next boot.
+func updateVCPUsParams(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel) error {
+	vmRecord, err := xenapi.VM.GetRecord(session, vmRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if vmRecord.VCPUsParams == nil {
+		vmRecord.VCPUsParams = make(map[string]string)
+	}
+	params := make(map[string]string)
+	if !plan.VCPUWeight.IsUnknown() && !plan.VCPUWeight.IsNull() {
+		params["weight"] = strconv.Itoa(int(plan.VCPUWeight.ValueInt32()))
+	}
+	if !plan.VCPUCap.IsUnknown() && !plan.VCPUCap.IsNull() {
+		params["cap"] = strconv.Itoa(int(plan.VCPUCap.ValueInt32()))
+	}
+	if !plan.VCPUMask.IsUnknown() && !plan.VCPUMask.IsNull() {
+		var mask []int32
+		diags := plan.VCPUMask.ElementsAs(ctx, &mask, false)
+		if diags.HasError() {
+			return errors.New("unable to read VM vcpu_mask")
+		}
+		cpus := make([]string, 0, len(mask))
+		for _, cpu := range mask {
+			cpus = append(cpus, strconv.Itoa(int(cpu)))
+		}
+		params["mask"] = strings.Join(cpus, ",")
+	}
+
+	for key, value := range params {
+		if oldValue, ok := vmRecord.VCPUsParams[key]; ok && oldValue == value {
+			continue
+		}
+		if vmRecord.PowerState == xenapi.VMPowerStateRunning && key != "mask" {
+			err = xenapi.VM.AddToVCPUsParamsLive(session, vmRef, key, value)
+		} else {
+			vmRecord.VCPUsParams[key] = value
+			err = xenapi.VM.SetVCPUsParams(session, vmRef, vmRecord.VCPUsParams)
+		}
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	return nil
+}
+
+func getVCPUsParamsFromVMRecord(ctx context.Context, vmRecord xenapi.VMRecord, data *vmResourceModel) error {
+	data.VCPUWeight = types.Int32Null()
+	if weight, ok := vmRecord.VCPUsParams["weight"]; ok {
+		weightInt, err := strconv.Atoi(weight)
+		if err != nil {
+			return errors.New("unable to convert VCPUs_params weight to an int value")
+		}
+		weightInt32, err := ToInt32(weightInt)
+		if err != nil {
+			return err
+		}
+		data.VCPUWeight = types.Int32Value(weightInt32)
+	}
+
+	data.VCPUCap = types.Int32Null()
+	if vcpuCap, ok := vmRecord.VCPUsParams["cap"]; ok {
+		capInt, err := strconv.Atoi(vcpuCap)
+		if err != nil {
+			return errors.New("unable to convert VCPUs_params cap to an int value")
+		}
+		capInt32, err := ToInt32(capInt)
+		if err != nil {
+			return err
+		}
+		data.VCPUCap = types.Int32Value(capInt32)
+	}
+
+	data.VCPUMask = types.ListNull(types.Int32Type)
+	if mask, ok := vmRecord.VCPUsParams["mask"]; ok {
+		cpus := []int32{}
+		for _, cpu := range strings.Split(mask, ",") {
+			cpu = strings.TrimSpace(cpu)
+			if cpu == "" {
+				continue
+			}
+			cpuInt, err := strconv.Atoi(cpu)
+			if err != nil {
+				return errors.New("unable to convert VCPUs_params mask to int values")
+			}
+			cpuInt32, err := ToInt32(cpuInt)
+			if err != nil {
+				return err
+			}
+			cpus = append(cpus, cpuInt32)
+		}
+		var diags diag.Diagnostics
+		data.VCPUMask, diags = types.ListValueFrom(ctx, types.Int32Type, cpus)
+		if diags.HasError() {
+			return errors.New("unable to update data for VM vcpu_mask")
+		}
+	}
+
+	return nil
+}
+
 func updateCorePerSocket(session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel) error {
 	platform, err := xenapi.VM.GetPlatform(session, vmRef)
 	if err != nil {
 		return errors.New(err.Error())
 	}
+	vcpusMax, err := getVCPUsMax(plan)
+	if err != nil {
+		return err
+	}
 	if plan.CorePerSocket.IsUnknown() {
-		// if user doesn't set cores-per-socket and it is not found in template, set it to VCPUs num as the default value
+		// if user doesn't set cores-per-socket and it is not found in template, set it to VCPUs max num as the default value
 		if _, ok := platform["cores-per-socket"]; !ok {
-			platform["cores-per-socket"] = plan.VCPUs.String()
+			platform["cores-per-socket"] = strconv.Itoa(vcpusMax)
 			err := xenapi.VM.SetPlatform(session, vmRef, platform)
 			if err != nil {
 				return errors.New(err.Error())
@@ -1058,9 +1520,8 @@ func updateCorePerSocket(session *xenapi.Session, vmRef xenapi.VMRef, plan vmRes
 		}
 	} else {
 		coresPerSocket := int(plan.CorePerSocket.ValueInt32())
-		vcpus := int(plan.VCPUs.ValueInt32())
-		if vcpus%coresPerSocket != 0 {
-			return fmt.Errorf("%d cores could not fit to %d cores-per-socket topology", vcpus, coresPerSocket)
+		if vcpusMax%coresPerSocket != 0 {
+			return fmt.Errorf("%d cores could not fit to %d cores-per-socket topology", vcpusMax, coresPerSocket)
 		}
 		platform["cores-per-socket"] = strconv.Itoa(coresPerSocket)
 		err := xenapi.VM.SetPlatform(session, vmRef, platform)
@@ -1126,9 +1587,136 @@ func updateBootMode(session *xenapi.Session, vmRef xenapi.VMRef, plan vmResource
 	return nil
 }
 
-func vmResourceModelUpdate(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel, state vmResourceModel) error {
+func setVMOtherConfigKeys(session *xenapi.Session, vmRef xenapi.VMRef, values map[string]string) error {
+	vmOtherConfig, err := xenapi.VM.GetOtherConfig(session, vmRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	for key, value := range values {
+		vmOtherConfig[key] = value
+	}
+	err = xenapi.VM.SetOtherConfig(session, vmRef, vmOtherConfig)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	return nil
+}
+
+// setBiosStrings sets the custom BIOS strings, it's only allowed before the first boot of the VM
+func setBiosStrings(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel) error {
+	biosStrings, keys, err := mergeTFManagedMap(ctx, map[string]string{}, "", plan.BiosStrings)
+	if err != nil {
+		return err
+	}
+	if len(biosStrings) == 0 {
+		return nil
+	}
+	err = xenapi.VM.SetBiosStrings(session, vmRef, biosStrings)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	return setVMOtherConfigKeys(session, vmRef, map[string]string{"tf_bios_strings_keys": keys})
+}
+
+// updateVMAdvancedSettings sets platform, xenstore_data, hvm_shadow_multiplier, actions_after_crash, has_vendor_device,
+// tags and blocked_operations, the settings which are not set by user keep the values inherited from the template.
+func updateVMAdvancedSettings(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel) error {
+	vmRecord, err := xenapi.VM.GetRecord(session, vmRef)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	isRunning := vmRecord.PowerState == xenapi.VMPowerStateRunning
+
+	platform, platformKeys, err := mergeTFManagedMap(ctx, vmRecord.Platform, vmRecord.OtherConfig["tf_platform_keys"], plan.Platform)
+	if err != nil {
+		return err
+	}
+	err = xenapi.VM.SetPlatform(session, vmRef, platform)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	xenstoreData, xenstoreDataKeys, err := mergeTFManagedMap(ctx, vmRecord.XenstoreData, vmRecord.OtherConfig["tf_xenstore_data_keys"], plan.XenstoreData)
+	if err != nil {
+		return err
+	}
+	err = xenapi.VM.SetXenstoreData(session, vmRef, xenstoreData)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	err = setVMOtherConfigKeys(session, vmRef, map[string]string{
+		"tf_platform_keys":      platformKeys,
+		"tf_xenstore_data_keys": xenstoreDataKeys,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !plan.HVMShadowMultiplier.IsUnknown() && plan.HVMShadowMultiplier.ValueFloat64() != vmRecord.HVMShadowMultiplier {
+		if isRunning {
+			err = xenapi.VM.SetShadowMultiplierLive(session, vmRef, plan.HVMShadowMultiplier.ValueFloat64())
+		} else {
+			err = xenapi.VM.SetHVMShadowMultiplier(session, vmRef, plan.HVMShadowMultiplier.ValueFloat64())
+		}
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	if !plan.ActionsAfterCrash.IsUnknown() {
+		err = xenapi.VM.SetActionsAfterCrash(session, vmRef, xenapi.OnCrashBehaviour(plan.ActionsAfterCrash.ValueString()))
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	if !plan.HasVendorDevice.IsUnknown() && plan.HasVendorDevice.ValueBool() != vmRecord.HasVendorDevice {
+		if isRunning {
+			return errors.New("unable to change has_vendor_device for a running VM, set allow_reboot_on_update to true to reboot the VM and apply the change")
+		}
+		err = xenapi.VM.SetHasVendorDevice(session, vmRef, plan.HasVendorDevice.ValueBool())
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	if !plan.Tags.IsUnknown() {
+		tags := []string{}
+		diags := plan.Tags.ElementsAs(ctx, &tags, false)
+		if diags.HasError() {
+			return errors.New("unable to read VM tags")
+		}
+		err = xenapi.VM.SetTags(session, vmRef, tags)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	if !plan.BlockedOperations.IsUnknown() {
+		planBlockedOperations := make(map[string]string)
+		diags := plan.BlockedOperations.ElementsAs(ctx, &planBlockedOperations, false)
+		if diags.HasError() {
+			return errors.New("unable to read VM blocked operations")
+		}
+		blockedOperations := make(map[xenapi.VMOperations]string)
+		for operation, reason := range planBlockedOperations {
+			blockedOperations[xenapi.VMOperations(operation)] = reason
+		}
+		err = xenapi.VM.SetBlockedOperations(session, vmRef, blockedOperations)
+		if err != nil {
+			return errors.New(err.Error())
+		}
+	}
+
+	return nil
+}
+
+func vmResourceModelUpdate(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel, state vmResourceModel) (err error) {
 	// set other config before getting the VM record for tf_ fields update
-	err := updateOtherConfigFromPlan(ctx, session, vmRef, plan)
+	err = updateOtherConfigFromPlan(ctx, session, vmRef, plan)
 	if err != nil {
 		return err
 	}
@@ -1158,6 +1746,25 @@ func vmResourceModelUpdate(ctx context.Context, session *xenapi.Session, vmRef x
 		return err
 	}
 
+	rebooting, err := shutdownVMForUpdate(ctx, session, vmRef, plan, state)
+	if err != nil {
+		return err
+	}
+	if rebooting {
+		// restore the power state of the VM which has been shut down for the update, even if the update fails
+		defer func() {
+			startErr := restartVMAfterUpdate(ctx, session, vmRef)
+			if startErr == nil {
+				return
+			}
+			if err == nil {
+				err = startErr
+				return
+			}
+			err = fmt.Errorf("%w, and %w", err, startErr)
+		}()
+	}
+
 	err = updateVMMemory(ctx, session, vmRef, plan, state)
 	if err != nil {
 		return err
@@ -1168,6 +1775,17 @@ func vmResourceModelUpdate(ctx context.Context, session *xenapi.Session, vmRef x
 		return err
 	}
 
+	err = updateVCPUsParams(ctx, session, vmRef, plan)
+	if err != nil {
+		return err
+	}
+
+	// update the platform before cores-per-socket and secureboot which are kept in the platform too
+	err = updateVMAdvancedSettings(ctx, session, vmRef, plan)
+	if err != nil {
+		return err
+	}
+
 	err = updateCorePerSocket(session, vmRef, plan)
 	if err != nil {
 		return err
@@ -1191,6 +1809,23 @@ func vmResourceModelUpdate(ctx context.Context, session *xenapi.Session, vmRef x
 	return nil
 }
 
+// restartVMAfterUpdate starts the VM which has been shut down for the update, unless it has been started already
+func restartVMAfterUpdate(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef) error {
+	vmPowerState, err := xenapi.VM.GetPowerState(session, vmRef)
+	if err != nil {
+		return errors.New("unable to get the power state of the VM shut down for the update: " + err.Error())
+	}
+	if vmPowerState != xenapi.VMPowerStateHalted {
+		return nil
+	}
+	tflog.Debug(ctx, "---> Start the VM after the update. <---")
+	err = xenapi.VM.Start(session, vmRef, false, false)
+	if err != nil {
+		return errors.New("unable to start the VM shut down for the update: " + err.Error())
+	}
+	return nil
+}
+
 func setVMResourceModel(ctx context.Context, session *xenapi.Session, vmRef xenapi.VMRef, plan vmResourceModel) error {
 	err := setOtherConfigWhenCreate(session, vmRef)
 	if err != nil {
@@ -1226,6 +1861,22 @@ func setVMResourceModel(ctx context.Context, session *xenapi.Session, vmRef xena
 		return err
 	}
 
+	err = updateVCPUsParams(ctx, session, vmRef, plan)
+	if err != nil {
+		return err
+	}
+
+	// set platform before cores-per-socket and secureboot which are kept in the platform too
+	err = updateVMAdvancedSettings(ctx, session, vmRef, plan)
+	if err != nil {
+		return err
+	}
+
+	err = setBiosStrings(ctx, session, vmRef, plan)
+	if err != nil {
+		return err
+	}
+
 	err = updateCorePerSocket(session, vmRef, plan)
 	if err != nil {
 		return err